@@ -1,90 +1,23 @@
 package main
 
 import (
+	"ava/internal/agent"
 	"ava/internal/tts"
+	"ava/internal/tts/sink/device"
 	"ava/internal/tts/volc"
 	"bufio"
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
-	"regexp"
 	"strings"
 
 	"github.com/cloudwego/eino-ext/components/model/openai"
 	"github.com/cloudwego/eino-ext/components/tool/duckduckgo/v2"
-	"github.com/cloudwego/eino/adk"
 	"github.com/cloudwego/eino/components/tool"
-	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
 )
 
-// extractToolCallsFromText 从文本中提取工具调用信息
-// 格式: <|FunctionCallBegin|>[{"name": "tool_name", "parameters": {...}}]<|FunctionCallEnd|>
-func extractToolCallsFromText(text string) []map[string]interface{} {
-	re := regexp.MustCompile(`<\|FunctionCallBegin\|>\[(.*?)\]<\|FunctionCallEnd\|>`)
-	matches := re.FindAllStringSubmatch(text, -1)
-
-	var toolCalls []map[string]interface{}
-	for _, match := range matches {
-		if len(match) > 1 {
-			var calls []map[string]interface{}
-			if err := json.Unmarshal([]byte(match[1]), &calls); err == nil {
-				toolCalls = append(toolCalls, calls...)
-			}
-		}
-	}
-	return toolCalls
-}
-
-// removeToolCallMarkers 移除文本中的工具调用标记
-func removeToolCallMarkers(text string) string {
-	re := regexp.MustCompile(`<\|FunctionCallBegin\|>.*?<\|FunctionCallEnd\|>`)
-	return strings.TrimSpace(re.ReplaceAllString(text, ""))
-}
-
-// removeSayTags 移除 say 标签，用于中间回复（不应该播放）
-func removeSayTags(text string) string {
-	// 移除 <say>...</say> 标签及其内容
-	re := regexp.MustCompile(`<say[^>]*>.*?</say>`)
-	return strings.TrimSpace(re.ReplaceAllString(text, ""))
-}
-
-// min 返回两个整数中的较小值
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
-// executeTool 执行工具调用
-func executeTool(ctx context.Context, toolName string, toolArgs string, webSearchTool interface{}) (string, error) {
-	// 类型断言为 InvokableTool
-	invokableTool, ok := webSearchTool.(interface {
-		InvokableRun(ctx context.Context, argumentsInJSON string, opts ...interface{}) (string, error)
-		Info(ctx context.Context) (*schema.ToolInfo, error)
-	})
-	if !ok {
-		return "", fmt.Errorf("工具类型错误")
-	}
-	// 获取工具的实际名称
-	toolInfo, _ := invokableTool.Info(ctx)
-	actualToolName := ""
-	if toolInfo != nil {
-		actualToolName = toolInfo.Name
-	}
-
-	// 匹配工具名称（支持多种可能的名称）
-	if toolName == actualToolName || toolName == "web_search" || toolName == "duckduckgo_text_search" {
-		// 执行网页搜索工具
-		return invokableTool.InvokableRun(ctx, toolArgs)
-	}
-
-	return "", fmt.Errorf("未知的工具: %s (可用工具: %s)", toolName, actualToolName)
-}
-
 func main() {
 	ctx := context.Background()
 
@@ -114,12 +47,12 @@ func main() {
 	}
 	defer ttsEngine.Close() // 确保资源清理
 
-	// 创建 Speaker
-	speaker := tts.NewSpeaker(ttsEngine)
-	tagAwareSpeaker := tts.NewTagAwareSpeaker(speaker)
-
-	// 创建处理用户输入的工具
-	handleInputTool := NewHandleUserInputTool(speaker)
+	// 创建 Speaker，输出到本地声卡
+	speaker, err := tts.NewSpeaker(ttsEngine, device.NewSink())
+	if err != nil {
+		log.Fatalf("创建 Speaker 失败: %v", err)
+	}
+	defer speaker.Close()
 
 	// 创建 DuckDuckGo 网页搜索工具
 	webSearchTool, err := duckduckgo.NewTextSearchTool(ctx, &duckduckgo.Config{})
@@ -170,6 +103,8 @@ Amy appears as a stunning statuesque forever-20-something woman with porcelain s
   * 如果不指定 emotion 和 context，将使用默认情感
 
 - <stop></stop>: 立即停止当前正在播放的语音。仅在 is_playing 为 true 时使用，当用户明确要求停止、打断播放，或者输入了有意义的指令需要停止当前播放时使用。
+- <pause></pause>: 暂停当前正在播放的语音，已合成的内容会保留。仅在 is_playing 为 true 时使用，当用户要求稍等、暂停时使用。
+- <resume></resume>: 从暂停的位置继续播放。仅在 is_paused 为 true 时使用，当用户要求继续播放时使用。
 - <ignore></ignore>: 忽略用户输入，继续播放当前语音。仅在 is_playing 为 true 时使用，当用户输入无关字符、无意义内容、随意输入（如"叽里呱啦"、"啊啊啊"、"123"等）时使用此标签。
 - 标签有 reason 属性，可以将理由写入到 reason。
 - 标签不能嵌套。
@@ -177,6 +112,7 @@ Amy appears as a stunning statuesque forever-20-something woman with porcelain s
 可用的工具：
 - get_playback_progress: 查询当前播放进度信息，包括：
   * is_playing: 是否正在播放（true 表示正在播放，false 表示没有播放）
+  * is_paused: 是否处于暂停状态
   * current_time: 当前播放时间（秒）
   * total_time: 总时长（秒）
   * remaining_time: 剩余时间（秒）
@@ -200,26 +136,33 @@ Amy appears as a stunning statuesque forever-20-something woman with porcelain s
 4. toolcall 时不需要使用任何标签
 `
 
-	// 使用 eino/adk 的 NewChatModelAgent 创建 agent
-	agentConfig := &adk.ChatModelAgentConfig{
-		Name:        "amy_assistant",
-		Description: "Amy Ravenwolf - A sassy AI assistant with voice capabilities",
-		Instruction: systemPrompt,
-		Model:       chatModel,
-		ToolsConfig: adk.ToolsConfig{
-			ToolsNodeConfig: compose.ToolsNodeConfig{
-				Tools: []tool.BaseTool{webSearchTool, timeQueryTool},
+	// 创建 VoiceAgent，流式回复中的 <say>/<stop> 等标签交给 Speaker 处理
+	voiceAgent, err := agent.NewVoiceAgent(ctx, agent.Config{
+		Name:           "amy_assistant",
+		Description:    "Amy Ravenwolf - A sassy AI assistant with voice capabilities",
+		Model:          chatModel,
+		Tools:          []tool.BaseTool{webSearchTool, timeQueryTool},
+		SystemPrompt:   systemPrompt,
+		Speaker:        speaker,
+		InjectProgress: true,
+		Hooks: agent.Hooks{
+			OnMessage: func(msg *schema.Message) {
+				if msg.Role == schema.Tool {
+					fmt.Printf("[调试] 收到工具结果: %s\n", msg.Content[:min(100, len(msg.Content))])
+				}
+			},
+			OnToolCalls: func(calls []schema.ToolCall) {
+				fmt.Printf("[工具调用] 检测到 %d 个工具调用\n", len(calls))
+			},
+			OnInterrupt: func(in tts.Interruption) {
+				log.Printf("[打断] 用户听到: %s", in.PlayedText)
 			},
 		},
-	}
-	chatModelAgent, err := adk.NewChatModelAgent(ctx, agentConfig)
+	})
 	if err != nil {
-		log.Fatalf("创建 ChatModelAgent 失败: %v", err)
+		log.Fatalf("创建 VoiceAgent 失败: %v", err)
 	}
 
-	// 保存对话历史
-	messages := []*schema.Message{}
-
 	fmt.Println("=== Agent 交互式对话 ===")
 	fmt.Println("输入您的问题（输入 'exit' 或 'quit' 退出）")
 	fmt.Println()
@@ -247,134 +190,15 @@ Amy appears as a stunning statuesque forever-20-something woman with porcelain s
 			continue
 		}
 
-		// 先调用工具查询播放进度
-		toolArgs := map[string]interface{}{}
-		toolArgsJSON, _ := json.Marshal(toolArgs)
-		toolResult, err := handleInputTool.InvokableRun(ctx, string(toolArgsJSON))
-		if err != nil {
-			log.Printf("调用 get_playback_progress 工具失败: %v", err)
-		} else {
-			fmt.Printf("[播放进度] %s\n", toolResult)
-		}
-
-		// 将工具结果和用户输入一起添加到消息中，让 LLM 根据进度信息自主判断
-		userMsgContent := input
-		if toolResult != "" {
-			userMsgContent = fmt.Sprintf("当前播放进度: %s\n\n用户输入: %s", toolResult, input)
-		}
-
-		// 添加用户消息到历史
-		userMsg := &schema.Message{
-			Role:    schema.User,
-			Content: userMsgContent,
-		}
-		messages = append(messages, userMsg)
-
-		// 使用 agent.Run() 方法处理请求
-		// agent 会自动处理工具调用循环
+		// 回复在流式输出时已经交给 Speaker 播放
 		fmt.Print("Agent: ")
-
-		// 创建 AgentInput
-		agentInput := &adk.AgentInput{
-			Messages: messages,
-		}
-
-		// 运行 agent，获取事件迭代器
-		iter := chatModelAgent.Run(ctx, agentInput)
-
-		var finalContent string
-		hasToolCalls := false
-
-		// 处理 agent 返回的事件
-		for {
-			event, ok := iter.Next()
-			if !ok {
-				// 迭代器结束，检查是否有最终回复
-				if hasToolCalls && finalContent == "" {
-					log.Printf("[调试] 迭代器结束，但还没有收到最终回复")
-				}
-				break
-			}
-
-			// 检查错误
-			if event.Err != nil {
-				log.Printf("Agent 运行出错: %v", event.Err)
-				break
-			}
-
-			// 检查输出
-			if event.Output != nil && event.Output.MessageOutput != nil {
-				msgOutput := event.Output.MessageOutput
-				// Message 类型是 *schema.Message 的别名，可以直接使用
-				msg := msgOutput.Message
-
-				if msg != nil {
-					// 调试输出
-					fmt.Printf("[调试] 收到消息，Role: %s, Content: %s, ToolCalls: %v\n",
-						msgOutput.Role,
-						msg.Content[:min(50, len(msg.Content))],
-						msg.ToolCalls != nil && len(msg.ToolCalls) > 0)
-
-					// 检查是否有工具调用
-					if msg.ToolCalls != nil && len(msg.ToolCalls) > 0 {
-						hasToolCalls = true
-						fmt.Printf("[工具调用] 检测到 %d 个工具调用\n", len(msg.ToolCalls))
-						// 工具调用会被 agent 自动处理，我们只需要等待最终回复
-						messages = append(messages, msg)
-						continue
-					}
-
-					// 检查文本中是否包含工具调用格式
-					toolCallsInText := extractToolCallsFromText(msg.Content)
-					if len(toolCallsInText) > 0 {
-						hasToolCalls = true
-						fmt.Printf("[工具调用] 检测到文本格式工具调用\n")
-						// 移除工具调用标记和 say 标签
-						cleanContent := removeToolCallMarkers(msg.Content)
-						cleanContent = removeSayTags(cleanContent)
-						if cleanContent != "" {
-							fmt.Printf("[中间回复] %s\n", cleanContent)
-						}
-						messages = append(messages, msg)
-						continue
-					}
-
-					// 没有工具调用，检查是否是最终回复
-					// 注意：工具调用后的回复可能 Role 是 Assistant
-					if msgOutput.Role == schema.Assistant && msg.Content != "" {
-						// 这是最终回复（没有工具调用）
-						finalContent = msg.Content
-						messages = append(messages, msg)
-						// 找到最终回复后，可以继续等待看是否还有更多事件，或者直接 break
-						// 但为了安全，我们继续循环，直到迭代器结束
-					} else if msgOutput.Role == schema.Tool {
-						// 工具结果，添加到消息历史
-						messages = append(messages, msg)
-						fmt.Printf("[调试] 收到工具结果: %s\n", msg.Content[:min(100, len(msg.Content))])
-					}
-				}
-			}
-
-			// 检查 Action（可能包含工具调用）
-			if event.Action != nil {
-				hasToolCalls = true
-				fmt.Printf("[工具调用] 检测到 Action\n")
-				// 工具调用会被 agent 自动处理，继续等待最终回复
-				continue
-			}
+		reply, err := voiceAgent.HandleUserInput(ctx, input)
+		if err != nil {
+			log.Printf("Agent 运行出错: %v", err)
 		}
-
-		// 只有最终回复才播放
-		if finalContent != "" {
-			// 确保最终回复中没有工具调用标记（以防万一）
-			finalContent = removeToolCallMarkers(finalContent)
-			fmt.Println(finalContent)
-
-			// 使用 TagAwareSpeaker 处理包含 XML 标签的响应
-			tagAwareSpeaker.Feed(finalContent)
-		} else if hasToolCalls {
-			// 如果有工具调用但没有最终回复，可能是迭代器提前结束了
-			log.Printf("[警告] 检测到工具调用，但没有收到最终回复。可能需要重新运行 agent")
+		if reply != nil && reply.Content != "" {
+			fmt.Println(reply.Content)
+		} else if reply != nil && reply.ToolCalls > 0 {
 			fmt.Println("[工具调用已完成，但没有收到最终回复]")
 		}
 
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"ava/internal/asr/volc"
+	"ava/internal/audio"
+	volctts "ava/internal/tts/volc"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 流式识别一个 16kHz 16bit 单声道的 WAV / PCM 文件，按实时速度推送音频并打印识别结果
+//
+//	VOLC_ACCESS_KEY=... VOLC_APP_KEY=... go run ./example/asr -in speech.wav
+func main() {
+	in := flag.String("in", "", "输入文件，16kHz 16bit 单声道 .wav 或 .pcm")
+	frameDuration := flag.Duration("frame", 100*time.Millisecond, "每包音频时长")
+	realtime := flag.Bool("realtime", true, "按实时速度推送音频")
+	flag.Parse()
+
+	if *in == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatalf("读取输入失败: %v", err)
+	}
+	if strings.EqualFold(filepath.Ext(*in), ".wav") && len(data) > 44 {
+		data = data[44:] // 跳过标准 WAV 头
+	}
+
+	cfg := volc.DefaultConfig()
+	cfg.Auth = volctts.AuthConfig{
+		AccessKey: os.Getenv("VOLC_ACCESS_KEY"),
+		AppKey:    os.Getenv("VOLC_APP_KEY"),
+	}
+	engine, err := volc.NewEngine(cfg)
+	if err != nil {
+		log.Fatalf("创建 ASR 引擎失败: %v", err)
+	}
+	defer engine.Close()
+
+	ctx := context.Background()
+	session, err := engine.Start(ctx)
+	if err != nil {
+		log.Fatalf("启动识别失败: %v", err)
+	}
+	defer session.Close()
+
+	go func() {
+		frameSize := cfg.Audio.SampleRate * cfg.Audio.BitDepth / 8 * cfg.Audio.Channels * int(*frameDuration/time.Millisecond) / 1000
+		for offset := 0; offset < len(data); offset += frameSize {
+			end := min(offset+frameSize, len(data))
+			frame := audio.Frame{Payload: data[offset:end], IsFirst: offset == 0, IsLast: end == len(data)}
+			if err := session.Write(ctx, frame); err != nil {
+				log.Printf("发送音频失败: %v", err)
+				return
+			}
+			if *realtime {
+				time.Sleep(*frameDuration)
+			}
+		}
+	}()
+
+	for t := range session.Transcripts() {
+		if t.Final {
+			fmt.Printf("[%6.2fs - %6.2fs] %s\n", t.StartTime, t.EndTime, t.Text)
+		} else {
+			fmt.Printf("  ... %s\n", t.Text)
+		}
+	}
+	if err := session.Err(); err != nil {
+		log.Fatalf("识别失败: %v", err)
+	}
+}
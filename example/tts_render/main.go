@@ -0,0 +1,87 @@
+package main
+
+import (
+	"ava/internal/config"
+	"ava/internal/tts"
+	"ava/internal/tts/render"
+	"ava/internal/tts/subtitle"
+	_ "ava/internal/tts/volc"
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 离线合成旁白：输入文本文件每个非空行作为一段，整篇合成为一个 WAV 文件和时间戳 JSON
+//
+//	go run ./example/tts_render -config configs/config.example.yaml -in script.txt -out narration.wav
+func main() {
+	configPath := flag.String("config", "configs/config.example.yaml", "配置文件路径")
+	in := flag.String("in", "", "输入文本文件，每行一段")
+	out := flag.String("out", "narration.wav", "输出文件，.wav 或 .pcm")
+	subtitles := flag.Bool("subtitles", false, "同时输出同名的 .srt 和 .vtt 字幕")
+	flag.Parse()
+
+	if *in == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+
+	ctx := context.Background()
+	engine, err := tts.NewFromOptions(ctx, cfg.TTS)
+	if err != nil {
+		log.Fatalf("创建 TTS 引擎失败: %v", err)
+	}
+	defer engine.Close()
+
+	lines, err := readLines(*in)
+	if err != nil {
+		log.Fatalf("读取输入失败: %v", err)
+	}
+
+	reqs := make([]tts.SayRequest, len(lines))
+	for i, line := range lines {
+		reqs[i] = tts.SayRequest{Text: line, Start: i == 0, End: i == len(lines)-1}
+	}
+
+	result, err := render.ToFile(ctx, engine, reqs, *out)
+	if err != nil {
+		log.Fatalf("合成失败: %v", err)
+	}
+	if *subtitles {
+		base := strings.TrimSuffix(*out, filepath.Ext(*out))
+		if err := os.WriteFile(base+".srt", []byte(subtitle.SRT(result.Timings)), 0o644); err != nil {
+			log.Fatalf("写入字幕失败: %v", err)
+		}
+		if err := os.WriteFile(base+".vtt", []byte(subtitle.VTT(result.Timings)), 0o644); err != nil {
+			log.Fatalf("写入字幕失败: %v", err)
+		}
+	}
+	fmt.Printf("已写入 %s，时长 %.2f 秒，%d 句\n", *out, result.Duration().Seconds(), len(result.Timings))
+}
+
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"ava/internal/audio"
+	"ava/internal/tts/volc"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// 把 TTS 合成的音频以 RTP 发送到 UDP 地址；不指定 -to 时发送到本地回环接收端并打印统计
+// 默认发送 L16（网络字节序），-codec pcmu 时合成 8kHz 并发送 G.711 μ-law（负载类型 0），可直接接入电话线路
+//
+//	VOLC_ACCESS_KEY=... VOLC_APP_KEY=... go run ./example/tts_rtp -to 127.0.0.1:4000 -codec pcmu -text "你好"
+func main() {
+	to := flag.String("to", "", "RTP 目标地址 host:port，为空时使用本地回环接收端")
+	text := flag.String("text", "你好，这是一段通过 RTP 发送的语音。", "要合成的文本")
+	codecName := flag.String("codec", "pcm", "发送的编码：pcm / pcmu / pcma")
+	payloadType := flag.Uint("pt", 96, "RTP 负载类型，pcmu / pcma 固定为 0 / 8")
+	flag.Parse()
+
+	ttsCodec := volc.DefaultCodecConfig()
+	switch *codecName {
+	case audio.CodecPCMU:
+		ttsCodec.SampleRate, ttsCodec.Encoding = 8000, audio.CodecPCMU
+		*payloadType = 0
+	case audio.CodecPCMA:
+		ttsCodec.SampleRate, ttsCodec.Encoding = 8000, audio.CodecPCMA
+		*payloadType = 8
+	}
+	ttsCodec.BitDepth = 0 // 使用编码的默认位深
+
+	ctx := context.Background()
+	engine, err := volc.NewVolcEngine(ctx, volc.AuthConfig{
+		AccessKey: os.Getenv("VOLC_ACCESS_KEY"),
+		AppKey:    os.Getenv("VOLC_APP_KEY"),
+	}, volc.NewVoiceConfig(&volc.VoiceMeilinNvyou), ttsCodec)
+	if err != nil {
+		log.Fatalf("创建 TTS 引擎失败: %v", err)
+	}
+	defer engine.Close()
+
+	streamer, err := engine.Start(ctx, "", nil)
+	if err != nil {
+		log.Fatalf("启动 TTS session 失败: %v", err)
+	}
+	if err := engine.Synthesize(ctx, *text, nil); err != nil {
+		log.Fatalf("合成失败: %v", err)
+	}
+	if err := engine.End(ctx); err != nil {
+		log.Fatalf("结束 session 失败: %v", err)
+	}
+
+	codec := streamer.CodecOption()
+	codec.PayloadType = uint8(*payloadType)
+
+	var receiver *audio.RTPReceiver
+	if *to == "" {
+		receiver, err = audio.ListenRTP("127.0.0.1:0", codec)
+		if err != nil {
+			log.Fatalf("监听 RTP 失败: %v", err)
+		}
+		defer receiver.Close()
+		*to = receiver.LocalAddr().String()
+		go func() {
+			// 丢弃收到的帧，只统计
+			for {
+				if _, err := receiver.Stream().Read(); err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	sender, err := audio.DialRTP(*to, codec)
+	if err != nil {
+		log.Fatalf("创建 RTP 发送端失败: %v", err)
+	}
+	defer sender.Close()
+
+	frames, err := streamer.Frames()
+	if err != nil {
+		log.Fatalf("读取音频帧失败: %v", err)
+	}
+	start := time.Now()
+	if err := sender.Send(ctx, frames); err != nil {
+		log.Fatalf("发送失败: %v", err)
+	}
+	stats := sender.Stats()
+	fmt.Printf("发送到 %s：%d 包，%d 字节，用时 %v\n", *to, stats.Packets, stats.Bytes, time.Since(start).Round(time.Millisecond))
+
+	if receiver != nil {
+		time.Sleep(100 * time.Millisecond)
+		stats := receiver.Stats()
+		fmt.Printf("回环接收：%d 包，丢包 %d，丢弃 %d\n", stats.Packets, stats.Lost, stats.Dropped)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"ava/internal/tts"
+	"ava/internal/tts/sink/device"
 	"ava/internal/tts/volc"
 	"context"
 	"fmt"
@@ -66,43 +67,32 @@ func main() {
 	// 	log.Fatalf("Failed to create tts engine: %v", err)
 	// }
 
-	// 创建 Speaker
-	speaker := tts.NewSpeaker(ttsEngine)
-
-	// 创建 context 用于控制进度打印 goroutine
-	progressCtx, cancelProgress := context.WithCancel(ctx)
-	defer cancelProgress()
+	// 创建 Speaker，输出到本地声卡（无声卡环境可以换成 sink.NewNullSink()）
+	speaker, err := tts.NewSpeaker(ttsEngine, device.NewSink())
+	if err != nil {
+		log.Fatalf("Failed to create speaker: %v", err)
+	}
+	defer speaker.Close()
 
-	// 启动进度打印 goroutine
+	// 订阅播放事件，按词高亮显示（不需要轮询 GetProgress）
+	events, unsubscribe := speaker.Subscribe(0)
+	defer unsubscribe()
+	finished := make(chan struct{})
 	go func() {
-		ticker := time.NewTicker(1 * time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-progressCtx.Done():
+		for e := range events {
+			switch e.Type {
+			case tts.EventFirstAudio:
+				fmt.Printf("[事件] session %d 开始播放\n", e.Session)
+			case tts.EventWord:
+				fmt.Printf("[%.2fs] %s\n", e.Time, e.Word.Word)
+			case tts.EventSentenceEnd:
+				fmt.Printf("[%.2fs] 句子结束: %s\n", e.Time, e.Sentence.Text)
+			case tts.EventUnderrun:
+				fmt.Printf("[%.2fs] 缓冲不足，播放中断\n", e.Time)
+			case tts.EventStopped, tts.EventFinished:
+				fmt.Printf("[事件] session %d %s (%.2fs)\n", e.Session, e.Type, e.Time)
+				close(finished)
 				return
-			case <-ticker.C:
-				progress := speaker.GetProgress()
-				if progress.TotalTime > 0 {
-					fmt.Printf("[进度] %.2f/%.2f 秒 (%.1f%%)",
-						progress.CurrentTime,
-						progress.TotalTime,
-						progress.Percentage)
-					if progress.CurrentWord != nil {
-						fmt.Printf(" - 当前词: %s", progress.CurrentWord.Word)
-					}
-					if progress.PlayedText != "" {
-						fmt.Printf(" - 已播放: %s", progress.PlayedText)
-					}
-					fmt.Println()
-				} else if progress.CurrentTime > 0 {
-					fmt.Printf("[进度] %.2f 秒 (总时长未知)", progress.CurrentTime)
-					if progress.PlayedText != "" {
-						fmt.Printf(" - 已播放: %s", progress.PlayedText)
-					}
-					fmt.Println()
-				}
 			}
 		}
 	}()
@@ -110,7 +100,7 @@ func main() {
 	// 合成并播放语音
 	//第一个调用：start=true 表示开始新 session，end=false 表示不结束 session
 	// 使用 context_texts 来调整语速
-	// if err := speaker.Say(tts.SayRequest{
+	// if err := speaker.Say(ctx, tts.SayRequest{
 	// 	Text:         "欢迎来到美丽新世界!",
 	// 	Start:        true,
 	// 	End:          false,
@@ -123,7 +113,7 @@ func main() {
 	// 第二个调用：start=false 表示继续使用当前 session，end=true 表示结束 session
 	// 注意：这会等待 session 真正完成后才返回
 	// 使用 context_texts 来调整情绪/语气
-	// if err := speaker.Say(tts.SayRequest{
+	// if err := speaker.Say(ctx, tts.SayRequest{
 	// 	Text:         "让我们一起跳舞吧!",
 	// 	Start:        false,
 	// 	End:          true,
@@ -134,7 +124,7 @@ func main() {
 	// }
 
 	// 第五个调用：测试情绪/语气调整（痛心语气）
-	if err := speaker.Say(tts.SayRequest{
+	if err := speaker.Say(ctx, tts.SayRequest{
 		Text:  "我逆转时空九十九次救你，你却次次死于同一支暗箭。谢珩，原来不是天要亡你……是你宁死也不肯为我活下去",
 		Start: true,
 		End:   true,
@@ -144,12 +134,13 @@ func main() {
 		log.Fatalf("Failed to synthesize: %v", err)
 	}
 
-	// 等待播放完成或3秒后停止播放
-	<-time.After(10 * time.Second)
-	speaker.Stop()
-
-	// 停止进度打印
-	cancelProgress()
+	// 等待播放完成，最多 10 秒后停止播放
+	select {
+	case <-finished:
+	case <-time.After(10 * time.Second):
+		speaker.Stop()
+		<-finished
+	}
 
 	// 打印最终进度
 	finalProgress := speaker.GetProgress()
@@ -0,0 +1,303 @@
+package agent
+
+import (
+	"ava/internal/tts"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrEmptyInput 用户输入为空
+var ErrEmptyInput = errors.New("agent: empty input")
+
+// Config VoiceAgent 配置
+type Config struct {
+	Name        string // agent 名称，默认 "voice_agent"
+	Description string // agent 描述，默认 "voice agent"
+
+	Model model.ToolCallingChatModel // 必填
+	Tools []tool.BaseTool
+
+	// SystemPrompt 系统提示词，按 text/template 渲染一次，可用的数据见 PromptData
+	SystemPrompt string
+	PromptVars   map[string]any // 模板中的 .Vars
+
+	Speaker   *tts.Speaker        // 必填，<say> 标签内的文本通过它播放
+	Segmenter tts.SegmenterConfig // 流式分句配置，零值使用默认值
+
+	// InjectProgress 为 true 时在每条用户消息前附上 ProgressTool 查询到的播放进度，
+	// 让模型根据播放状态决定 <stop>/<ignore> 等
+	InjectProgress bool
+
+	MaxIterations int // 一次输入内模型调用的最大轮数，默认使用 adk 的默认值
+	Hooks         Hooks
+}
+
+// Hooks VoiceAgent 事件回调，都在 HandleUserInput 所在的 goroutine 中同步调用
+type Hooks struct {
+	OnChunk     func(text string)             // 助手回复的流式文本片段
+	OnMessage   func(msg *schema.Message)     // 每条加入历史的完整消息（用户、助手、工具结果）
+	OnToolCalls func(calls []schema.ToolCall) // 模型发起工具调用，包括以文本形式输出的调用
+	OnReply     func(content string)          // 本轮最终回复，已移除工具调用标记
+	OnInterrupt func(in tts.Interruption)     // <stop> 打断播放后调用，历史已经被改写
+}
+
+// PromptData 渲染 SystemPrompt 时的模板数据
+type PromptData struct {
+	Name  string
+	Tools []*schema.ToolInfo
+	Vars  map[string]any
+}
+
+// Reply 一次 HandleUserInput 的结果
+type Reply struct {
+	Content   string            // 最终回复（保留 <say> 等标签），没有最终回复时为空
+	Messages  []*schema.Message // 本轮产生的所有消息，不包括用户消息
+	ToolCalls int               // 本轮工具调用次数
+}
+
+// VoiceAgent 语音对话 agent：把用户输入交给 adk ChatModelAgent，
+// 流式回复中的 <say>/<stop> 等标签交给 TagAwareSpeaker 处理，并维护对话历史
+type VoiceAgent struct {
+	cfg      Config
+	agent    adk.Agent
+	speaker  *tts.TagAwareSpeaker
+	progress *ProgressTool
+	history  *History
+
+	mu sync.Mutex // 串行执行 HandleUserInput
+}
+
+// NewVoiceAgent 创建 VoiceAgent
+func NewVoiceAgent(ctx context.Context, cfg Config) (*VoiceAgent, error) {
+	if cfg.Model == nil {
+		return nil, errors.New("agent: model is required")
+	}
+	if cfg.Speaker == nil {
+		return nil, errors.New("agent: speaker is required")
+	}
+	if cfg.Name == "" {
+		cfg.Name = "voice_agent"
+	}
+	if cfg.Description == "" {
+		cfg.Description = "voice agent"
+	}
+
+	instruction, err := renderPrompt(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	agentConfig := &adk.ChatModelAgentConfig{
+		Name:          cfg.Name,
+		Description:   cfg.Description,
+		Instruction:   instruction,
+		Model:         cfg.Model,
+		MaxIterations: cfg.MaxIterations,
+	}
+	if len(cfg.Tools) > 0 {
+		agentConfig.ToolsConfig = adk.ToolsConfig{
+			ToolsNodeConfig: compose.ToolsNodeConfig{Tools: cfg.Tools},
+		}
+	}
+	chatModelAgent, err := adk.NewChatModelAgent(ctx, agentConfig)
+	if err != nil {
+		return nil, fmt.Errorf("agent: create chat model agent: %w", err)
+	}
+
+	a := &VoiceAgent{
+		cfg:      cfg,
+		agent:    chatModelAgent,
+		speaker:  tts.NewTagAwareSpeaker(cfg.Speaker, cfg.Segmenter),
+		progress: NewProgressTool(cfg.Speaker),
+		history:  NewHistory(),
+	}
+	a.speaker.OnInterrupt(a.onInterrupt)
+	return a, nil
+}
+
+// History 返回对话历史
+func (a *VoiceAgent) History() *History {
+	return a.history
+}
+
+// HandleUserInput 处理一条用户输入：调用模型（含工具调用循环），边接收边播放回复
+// 多次调用会串行执行；出错时本轮已收到的消息仍然保留在历史中
+func (a *VoiceAgent) HandleUserInput(ctx context.Context, text string) (*Reply, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrEmptyInput
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	userMsg := schema.UserMessage(a.userContent(ctx, text))
+	a.history.Add(userMsg)
+	a.onMessage(userMsg)
+
+	reply := &Reply{}
+	iter := a.agent.Run(ctx, &adk.AgentInput{
+		Messages:        a.history.Messages(),
+		EnableStreaming: true,
+	})
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if event.Err != nil {
+			return reply, fmt.Errorf("agent: run: %w", event.Err)
+		}
+		if event.Output == nil || event.Output.MessageOutput == nil {
+			continue
+		}
+
+		msg, err := a.receive(ctx, event.Output.MessageOutput)
+		if err != nil {
+			return reply, err
+		}
+		if msg == nil {
+			continue
+		}
+		a.history.Add(msg)
+		reply.Messages = append(reply.Messages, msg)
+		a.onMessage(msg)
+		a.handleMessage(reply, msg)
+	}
+
+	if reply.Content == "" && reply.ToolCalls > 0 {
+		logrus.Warnf("agent: tool calls finished without a final reply")
+	}
+	return reply, nil
+}
+
+// userContent 按配置在用户输入前附上播放进度
+func (a *VoiceAgent) userContent(ctx context.Context, text string) string {
+	if !a.cfg.InjectProgress {
+		return text
+	}
+	progress, err := a.progress.InvokableRun(ctx, "{}")
+	if err != nil {
+		logrus.Warnf("agent: failed to query playback progress: %v", err)
+		return text
+	}
+	return fmt.Sprintf("当前播放进度: %s\n\n用户输入: %s", progress, text)
+}
+
+// receive 读取一条输出消息；助手的流式回复会边接收边喂给 TagAwareSpeaker
+func (a *VoiceAgent) receive(ctx context.Context, out *adk.MessageVariant) (*schema.Message, error) {
+	if !out.IsStreaming {
+		return out.Message, nil
+	}
+
+	stream := out.MessageStream
+	defer stream.Close()
+
+	speak := out.Role == schema.Assistant
+	var chunks []*schema.Message
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("agent: read stream: %w", err)
+		}
+		chunks = append(chunks, chunk)
+		if speak && chunk.Content != "" {
+			// 工具调用等 <say> 标签之外的内容会被 TagParser 忽略
+			a.speaker.Feed(ctx, chunk.Content)
+			if a.cfg.Hooks.OnChunk != nil {
+				a.cfg.Hooks.OnChunk(chunk.Content)
+			}
+		}
+	}
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+	msg, err := schema.ConcatMessages(chunks)
+	if err != nil {
+		return nil, fmt.Errorf("agent: concat stream: %w", err)
+	}
+	return msg, nil
+}
+
+// handleMessage 区分工具调用和最终回复
+func (a *VoiceAgent) handleMessage(reply *Reply, msg *schema.Message) {
+	if msg.Role != schema.Assistant {
+		return
+	}
+
+	calls := msg.ToolCalls
+	if len(calls) == 0 {
+		// 部分模型以文本形式输出工具调用
+		calls = ExtractToolCalls(msg.Content)
+	}
+	if len(calls) > 0 {
+		reply.ToolCalls += len(calls)
+		if a.cfg.Hooks.OnToolCalls != nil {
+			a.cfg.Hooks.OnToolCalls(calls)
+		}
+		return
+	}
+
+	if msg.Content != "" {
+		reply.Content = RemoveToolCallMarkers(msg.Content)
+		if a.cfg.Hooks.OnReply != nil {
+			a.cfg.Hooks.OnReply(reply.Content)
+		}
+	}
+}
+
+func (a *VoiceAgent) onMessage(msg *schema.Message) {
+	if a.cfg.Hooks.OnMessage != nil {
+		a.cfg.Hooks.OnMessage(msg)
+	}
+}
+
+func (a *VoiceAgent) onInterrupt(in tts.Interruption) {
+	if !a.history.Interrupt(in) {
+		logrus.Debugf("agent: no reply matches interrupted session")
+	}
+	if a.cfg.Hooks.OnInterrupt != nil {
+		a.cfg.Hooks.OnInterrupt(in)
+	}
+}
+
+// renderPrompt 渲染系统提示词模板
+func renderPrompt(ctx context.Context, cfg Config) (string, error) {
+	if cfg.SystemPrompt == "" {
+		return "", nil
+	}
+	tmpl, err := template.New("system").Option("missingkey=error").Parse(cfg.SystemPrompt)
+	if err != nil {
+		return "", fmt.Errorf("agent: parse system prompt: %w", err)
+	}
+
+	data := PromptData{Name: cfg.Name, Vars: cfg.PromptVars}
+	for _, t := range cfg.Tools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return "", fmt.Errorf("agent: tool info: %w", err)
+		}
+		data.Tools = append(data.Tools, info)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("agent: render system prompt: %w", err)
+	}
+	return b.String(), nil
+}
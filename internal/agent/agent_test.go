@@ -0,0 +1,162 @@
+package agent
+
+import (
+	"ava/internal/tts"
+	"ava/internal/tts/sink"
+	"ava/internal/tts/volc"
+	"ava/internal/tts/volc/volctest"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// fakeModel 依次返回预设的回复，每个回复按 rune 拆成流式片段
+type fakeModel struct {
+	mu      sync.Mutex
+	replies []*schema.Message
+	inputs  [][]*schema.Message
+}
+
+func (m *fakeModel) next(input []*schema.Message) *schema.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inputs = append(m.inputs, input)
+	msg := m.replies[0]
+	m.replies = m.replies[1:]
+	return msg
+}
+
+func (m *fakeModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return m.next(input), nil
+}
+
+func (m *fakeModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg := m.next(input)
+	chunks := []*schema.Message{{Role: schema.Assistant, ToolCalls: msg.ToolCalls}}
+	for _, r := range msg.Content {
+		chunks = append(chunks, &schema.Message{Role: schema.Assistant, Content: string(r)})
+	}
+	return schema.StreamReaderFromArray(chunks), nil
+}
+
+func (m *fakeModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return m, nil
+}
+
+type echoTool struct{}
+
+func (echoTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "echo", Desc: "echo"}, nil
+}
+
+func (echoTool) InvokableRun(ctx context.Context, args string, opts ...tool.Option) (string, error) {
+	return "pong", nil
+}
+
+func TestVoiceAgent(t *testing.T) {
+	spoken := make(chan string, 16)
+	srv := volctest.NewServer(func(s *volctest.Session) {
+		_ = s.SendStarted()
+		for text := range s.Texts() {
+			spoken <- text
+			_ = s.Speak(text)
+		}
+		_ = s.SendFinished()
+	})
+	defer srv.Close()
+
+	engine, err := volc.NewVolcEngineWithConfig(context.Background(), volc.Config{
+		Endpoint: srv.URL,
+		Auth:     volc.AuthConfig{AccessKey: "test-access", AppKey: "test-app"},
+		Voice:    volc.NewVoiceConfig(&volc.VoiceProfile{VoiceType: "test_voice", ResourceID: "test-resource"}),
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	defer engine.Close()
+	speaker, err := tts.NewSpeaker(engine, sink.NewMemorySink())
+	if err != nil {
+		t.Fatalf("new speaker: %v", err)
+	}
+	defer speaker.Close()
+
+	fm := &fakeModel{replies: []*schema.Message{
+		{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{ID: "call_1", Type: "function", Function: schema.FunctionCall{Name: "echo", Arguments: "{}"}}}},
+		{Role: schema.Assistant, Content: "<say>你好，测试通过。</say>"},
+	}}
+	var toolCalls []schema.ToolCall
+	a, err := NewVoiceAgent(context.Background(), Config{
+		Model:        fm,
+		Tools:        []tool.BaseTool{echoTool{}},
+		SystemPrompt: "你是{{.Vars.Role}}，可用工具：{{range .Tools}}{{.Name}}{{end}}",
+		PromptVars:   map[string]any{"Role": "测试助手"},
+		Speaker:      speaker,
+		Hooks: Hooks{
+			OnToolCalls: func(calls []schema.ToolCall) { toolCalls = append(toolCalls, calls...) },
+		},
+	})
+	if err != nil {
+		t.Fatalf("new agent: %v", err)
+	}
+
+	reply, err := a.HandleUserInput(context.Background(), "  你好  ")
+	if err != nil {
+		t.Fatalf("handle input: %v", err)
+	}
+	if reply.Content != "<say>你好，测试通过。</say>" {
+		t.Fatalf("unexpected reply: %q", reply.Content)
+	}
+	if reply.ToolCalls != 1 || len(toolCalls) != 1 || toolCalls[0].Function.Name != "echo" {
+		t.Fatalf("unexpected tool calls: %d %+v", reply.ToolCalls, toolCalls)
+	}
+
+	// 用户、工具调用、工具结果、最终回复
+	roles := []schema.RoleType{schema.User, schema.Assistant, schema.Tool, schema.Assistant}
+	msgs := a.History().Messages()
+	if len(msgs) != len(roles) {
+		t.Fatalf("unexpected history length: %d", len(msgs))
+	}
+	for i, role := range roles {
+		if msgs[i].Role != role {
+			t.Fatalf("message %d role = %s, want %s", i, msgs[i].Role, role)
+		}
+	}
+	if msgs[0].Content != "你好" || msgs[2].Content != "pong" {
+		t.Fatalf("unexpected history: %q %q", msgs[0].Content, msgs[2].Content)
+	}
+	if system := fm.inputs[0][0]; system.Role != schema.System || system.Content != "你是测试助手，可用工具：echo" {
+		t.Fatalf("unexpected system prompt: %+v", system)
+	}
+
+	var texts []string
+	timeout := time.After(2 * time.Second)
+	for strings.Join(texts, "") != "你好，测试通过。" {
+		select {
+		case text := <-spoken:
+			texts = append(texts, text)
+		case <-timeout:
+			t.Fatalf("speaker did not receive reply, got %q", texts)
+		}
+	}
+
+	if _, err := a.HandleUserInput(context.Background(), " "); err != ErrEmptyInput {
+		t.Fatalf("expected ErrEmptyInput, got %v", err)
+	}
+}
+
+func TestExtractToolCalls(t *testing.T) {
+	text := `好的<|FunctionCallBegin|>[{"name": "web_search", "parameters": {"query": "天气"}}]<|FunctionCallEnd|>`
+	calls := ExtractToolCalls(text)
+	if len(calls) != 1 || calls[0].Function.Name != "web_search" || calls[0].Function.Arguments != `{"query": "天气"}` {
+		t.Fatalf("unexpected calls: %+v", calls)
+	}
+	if got := RemoveToolCallMarkers(text); got != "好的" {
+		t.Fatalf("unexpected text: %q", got)
+	}
+}
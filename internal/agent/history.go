@@ -0,0 +1,143 @@
+// Package agent 语音对话 agent 的公共组件
+package agent
+
+import (
+	"ava/internal/tts"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// DefaultInterruptedNote 被打断的回复末尾追加的说明，让 LLM 知道后面的内容用户没有听到
+const DefaultInterruptedNote = "[说到这里被用户打断了，用户只听到了以上内容]"
+
+// ExtraInterrupted 被改写的消息会在 Message.Extra 中设置该键为 true
+const ExtraInterrupted = "interrupted"
+
+var sayBlockRe = regexp.MustCompile(`(?s)(<say[^>]*>)(.*?)(</say>)`)
+
+// History 对话历史，能在播放被打断时把最后一条助手回复改写为用户实际听到的内容
+// 并发安全，可以在 TagAwareSpeaker.OnInterrupt 回调中调用 Interrupt
+type History struct {
+	InterruptedNote string // 改写后追加的说明，默认 DefaultInterruptedNote
+
+	mu       sync.Mutex
+	messages []*schema.Message
+}
+
+// NewHistory 创建对话历史
+func NewHistory() *History {
+	return &History{InterruptedNote: DefaultInterruptedNote}
+}
+
+// Add 追加消息
+func (h *History) Add(msgs ...*schema.Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, msgs...)
+}
+
+// Messages 返回历史消息的副本，可以直接作为 agent 输入
+func (h *History) Messages() []*schema.Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	result := make([]*schema.Message, len(h.messages))
+	copy(result, h.messages)
+	return result
+}
+
+// Len 返回消息数量
+func (h *History) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.messages)
+}
+
+// Interrupt 找到包含被打断 session 的最后一条助手回复，截断到用户实际听到的位置并标注为被打断
+// 之前的 <say> 内容保留，被打断的 <say> 只保留已播放的部分，之后的内容全部丢弃
+// 找不到对应的回复时返回 false
+func (h *History) Interrupt(in tts.Interruption) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := len(h.messages) - 1; i >= 0; i-- {
+		msg := h.messages[i]
+		if msg.Role != schema.Assistant || !strings.Contains(msg.Content, "<say") {
+			continue
+		}
+		content, ok := truncateReply(msg.Content, in)
+		if !ok {
+			continue
+		}
+
+		note := h.InterruptedNote
+		if note == "" {
+			note = DefaultInterruptedNote
+		}
+		rewritten := *msg
+		rewritten.Content = strings.TrimSpace(content + "\n" + note)
+		rewritten.Extra = make(map[string]any, len(msg.Extra)+1)
+		for k, v := range msg.Extra {
+			rewritten.Extra[k] = v
+		}
+		rewritten.Extra[ExtraInterrupted] = true
+		h.messages[i] = &rewritten
+		return true
+	}
+	return false
+}
+
+// truncateReply 按 Interruption 截断带 <say> 标签的回复
+func truncateReply(content string, in tts.Interruption) (string, bool) {
+	blocks := sayBlockRe.FindAllStringSubmatchIndex(content, -1)
+	if len(blocks) == 0 {
+		return "", false
+	}
+
+	current := findSession(content, blocks, in.Texts)
+	if current < 0 {
+		return "", false
+	}
+
+	b := blocks[current]
+	open, inner, close := content[b[2]:b[3]], content[b[4]:b[5]], content[b[6]:b[7]]
+	played := tts.AlignPlayed(inner, in.PlayedWords)
+
+	result := strings.TrimSpace(content[:b[0]])
+	if strings.TrimSpace(played) != "" {
+		result += open + played + close
+	}
+	return result, true
+}
+
+// findSession 返回被打断的 session 对应的 <say> 下标，找不到时返回 -1
+// 优先匹配包含 session 全部文本的块，其次匹配包含首段文本的块，都从最后一个块往前找，
+// 避免回复中重复的短开场白（如两个 <say> 都以"好的。"开头）匹配到更早的块
+func findSession(content string, blocks [][]int, texts []string) int {
+	var first string
+	for _, t := range texts {
+		if t = compactSpace(t); t != "" {
+			first = t
+			break
+		}
+	}
+	if first == "" {
+		return -1
+	}
+	for _, target := range []string{compactSpace(strings.Join(texts, "")), first} {
+		for i := len(blocks) - 1; i >= 0; i-- {
+			b := blocks[i]
+			if strings.Contains(compactSpace(content[b[4]:b[5]]), target) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// compactSpace 去掉所有空白，分段提交的文本与原文之间的空格可能不一致
+func compactSpace(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"ava/internal/tts"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestHistoryInterrupt(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		in      tts.Interruption
+		want    string
+		ok      bool
+	}{
+		{
+			name:    "truncate current say",
+			content: `<say emotion="happy">今天天气很好，适合出去走走。</say>`,
+			in: tts.Interruption{
+				Texts:       []string{"今天天气很好，", "适合出去走走。"},
+				PlayedWords: []string{"今", "天", "天", "气", "很", "好"},
+			},
+			want: `<say emotion="happy">今天天气很好，</say>` + "\n" + DefaultInterruptedNote,
+			ok:   true,
+		},
+		{
+			name:    "keep earlier say and drop later ones",
+			content: `<say>第一句。</say><say>Hello there, how are you?</say><say>第三句。</say>`,
+			in: tts.Interruption{
+				Texts:       []string{"Hello there,", "how are you?"},
+				PlayedWords: []string{"Hello", "there"},
+			},
+			want: `<say>第一句。</say><say>Hello there,</say>` + "\n" + DefaultInterruptedNote,
+			ok:   true,
+		},
+		{
+			name:    "repeated opener",
+			content: `<say>好的。我先查一下。</say><say>好的。查到了，明天会下雨。</say>`,
+			in: tts.Interruption{
+				Texts:       []string{"好的。", "查到了，", "明天会下雨。"},
+				PlayedWords: []string{"好", "的", "查", "到", "了"},
+			},
+			want: `<say>好的。我先查一下。</say><say>好的。查到了，</say>` + "\n" + DefaultInterruptedNote,
+			ok:   true,
+		},
+		{
+			name:    "nothing played",
+			content: `<say>第一句。</say><say>第二句。</say>`,
+			in:      tts.Interruption{Texts: []string{"第二句。"}},
+			want:    `<say>第一句。</say>` + "\n" + DefaultInterruptedNote,
+			ok:      true,
+		},
+		{
+			name:    "unknown session",
+			content: `<say>第一句。</say>`,
+			in:      tts.Interruption{Texts: []string{"别的内容"}},
+			want:    `<say>第一句。</say>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHistory()
+			h.Add(
+				&schema.Message{Role: schema.User, Content: "你好"},
+				&schema.Message{Role: schema.Assistant, Content: tt.content},
+			)
+			if ok := h.Interrupt(tt.in); ok != tt.ok {
+				t.Fatalf("Interrupt() = %v, want %v", ok, tt.ok)
+			}
+			msgs := h.Messages()
+			last := msgs[len(msgs)-1]
+			if last.Content != tt.want {
+				t.Fatalf("content = %q, want %q", last.Content, tt.want)
+			}
+			if interrupted, _ := last.Extra[ExtraInterrupted].(bool); interrupted != tt.ok {
+				t.Fatalf("interrupted = %v, want %v", interrupted, tt.ok)
+			}
+		})
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package agent
 
 import (
 	"ava/internal/tts"
@@ -9,24 +9,28 @@ import (
 	"github.com/cloudwego/eino/schema"
 )
 
-// HandleUserInputTool 处理用户输入的工具，查询播放进度并返回决策建议
-type HandleUserInputTool struct {
+// ProgressToolName 播放进度工具的名称
+const ProgressToolName = "fetch_playback_progress"
+
+// ProgressTool 查询播放进度的工具，VoiceAgent 用它在用户消息前注入播放状态，
+// 也可以直接注册给模型调用
+type ProgressTool struct {
 	speaker *tts.Speaker
 }
 
-func NewHandleUserInputTool(speaker *tts.Speaker) *HandleUserInputTool {
-	return &HandleUserInputTool{
+func NewProgressTool(speaker *tts.Speaker) *ProgressTool {
+	return &ProgressTool{
 		speaker: speaker,
 	}
 }
 
-func (ht *HandleUserInputTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+func (ht *ProgressTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
 	return &schema.ToolInfo{
-		Name: "fetch_playback_progress",
+		Name: ProgressToolName,
 		Desc: `Query current playback progress information. 
 This tool should be called when you need to check the current playback status before responding to user input.
 It returns:
-- Current playback time and total duration
+- Current playback time and total duration (total_final is false while speech is still being synthesized; total_time and remaining_time are then lower bounds)
 - Playback percentage
 - Currently playing word (if available)
 - Already played text (all words that have finished playing)
@@ -41,7 +45,7 @@ You should use this information to decide whether to interrupt the current playb
 	}, nil
 }
 
-func (ht *HandleUserInputTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+func (ht *ProgressTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
 	var args struct {
 		UserInput string `json:"user_input"`
 	}
@@ -51,17 +55,18 @@ func (ht *HandleUserInputTool) InvokableRun(ctx context.Context, argumentsInJSON
 	// 查询当前播放进度
 	progress := ht.speaker.GetProgress()
 
-	// 计算剩余时间
-	remainingTime := progress.TotalTime - progress.CurrentTime
-	isPlaying := progress.CurrentTime > 0 && progress.Percentage < 100
+	// 计算剩余时间，合成还没结束时为下限
+	remainingTime := max(progress.TotalTime-progress.CurrentTime, 0)
 
 	// 构建进度信息（只返回数据，不包含决策）
 	progressInfo := map[string]interface{}{
 		"current_time":   progress.CurrentTime,
 		"total_time":     progress.TotalTime,
+		"total_final":    progress.Final,
 		"remaining_time": remainingTime,
 		"percentage":     progress.Percentage,
-		"is_playing":     isPlaying,
+		"is_playing":     progress.Playing,
+		"is_paused":      ht.speaker.IsPaused(),
 		"played_text":    progress.PlayedText, // 已播放的文本
 	}
 
@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+var (
+	textToolCallRe   = regexp.MustCompile(`(?s)<\|FunctionCallBegin\|>\[(.*?)\]<\|FunctionCallEnd\|>`)
+	toolCallMarkerRe = regexp.MustCompile(`(?s)<\|FunctionCallBegin\|>.*?<\|FunctionCallEnd\|>`)
+	sayTagRe         = regexp.MustCompile(`(?s)<say[^>]*>.*?</say>`)
+)
+
+// ExtractToolCalls 从文本中提取模型以文本形式输出的工具调用
+// 格式: <|FunctionCallBegin|>[{"name": "tool_name", "parameters": {...}}]<|FunctionCallEnd|>
+func ExtractToolCalls(text string) []schema.ToolCall {
+	var toolCalls []schema.ToolCall
+	for _, match := range textToolCallRe.FindAllStringSubmatch(text, -1) {
+		var calls []struct {
+			Name       string          `json:"name"`
+			Parameters json.RawMessage `json:"parameters"`
+		}
+		if err := json.Unmarshal([]byte("["+match[1]+"]"), &calls); err != nil {
+			continue
+		}
+		for _, call := range calls {
+			args := string(call.Parameters)
+			if args == "" {
+				args = "{}"
+			}
+			toolCalls = append(toolCalls, schema.ToolCall{
+				Type:     "function",
+				Function: schema.FunctionCall{Name: call.Name, Arguments: args},
+			})
+		}
+	}
+	return toolCalls
+}
+
+// RemoveToolCallMarkers 移除文本中的工具调用标记
+func RemoveToolCallMarkers(text string) string {
+	return strings.TrimSpace(toolCallMarkerRe.ReplaceAllString(text, ""))
+}
+
+// RemoveSayTags 移除 <say>...</say> 标签及其内容，用于显示不需要播放的中间回复
+func RemoveSayTags(text string) string {
+	return strings.TrimSpace(sayTagRe.ReplaceAllString(text, ""))
+}
@@ -0,0 +1,51 @@
+// Package asr 流式语音识别
+package asr
+
+import (
+	"ava/internal/audio"
+	"context"
+	"errors"
+)
+
+var (
+	// ErrSessionFinished 已经发送过最后一帧后继续 Write
+	ErrSessionFinished = errors.New("asr: session finished")
+	// ErrSessionClosed session 已经关闭
+	ErrSessionClosed = errors.New("asr: session closed")
+)
+
+// Word 一个词的识别结果
+type Word struct {
+	Text      string  `json:"text"`
+	StartTime float64 `json:"startTime"` // 秒，相对 session 开始的音频
+	EndTime   float64 `json:"endTime"`
+}
+
+// Transcript 一个分句的识别结果
+// 同一个分句会先以 Final=false 多次输出中间结果，确定后输出一次 Final=true
+type Transcript struct {
+	Text      string  `json:"text"`
+	StartTime float64 `json:"startTime"` // 秒，相对 session 开始的音频，服务端不返回时间戳时为 0
+	EndTime   float64 `json:"endTime"`
+	Final     bool    `json:"final"` // 该分句已经确定，不会再变化
+	Words     []Word  `json:"words,omitempty"`
+}
+
+// Session 一次流式识别
+type Session interface {
+	// Write 推送一帧音频（格式由引擎配置决定），frame.IsLast 表示音频结束，之后服务端会输出剩余的结果
+	Write(ctx context.Context, frame audio.Frame) error
+	// Transcripts 识别结果，session 结束（服务端返回最后一包、出错或 Close）后关闭
+	Transcripts() <-chan Transcript
+	// Err Transcripts 关闭后返回结束原因，正常结束或主动 Close 时为 nil
+	Err() error
+	// Close 立即结束 session 并释放连接，可以重复调用
+	Close() error
+}
+
+// Engine 流式语音识别引擎
+// Start 接收 context：取消 ctx 会立即返回 ctx.Err()，ctx 的 deadline 用于限制建立 session 的延迟
+type Engine interface {
+	Start(ctx context.Context) (Session, error) // 启动一次识别
+	Close() error                               // 关闭引擎并清理资源
+}
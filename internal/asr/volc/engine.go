@@ -0,0 +1,198 @@
+// Package volc 火山引擎大模型流式语音识别（WebSocket），复用 tts/volc 的二进制协议编解码
+package volc
+
+import (
+	"ava/internal/asr"
+	"ava/internal/audio"
+	volctts "ava/internal/tts/volc"
+	"ava/pkg/websocket"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultEndpoint 火山引擎大模型流式语音识别的服务地址
+	DefaultEndpoint = "wss://openspeech.bytedance.com/api/v3/sauc/bigmodel"
+	// DefaultResourceID 按时长计费的小时版资源
+	DefaultResourceID = "volc.bigasr.sauc.duration"
+)
+
+// Config 识别配置
+type Config struct {
+	Endpoint   string // 服务地址，默认 DefaultEndpoint
+	Auth       volctts.AuthConfig
+	ResourceID string            // X-Api-Resource-Id，默认 DefaultResourceID
+	Audio      audio.CodecOption // 输入音频格式，零值字段使用 pcm 16000Hz 16bit 单声道
+	UID        string            // 请求中的 user.uid（可选）
+
+	EnableITN  bool // 文本规范化（如 "一百" -> "100"）
+	EnablePunc bool // 标点
+	EnableDDC  bool // 语义顺滑，去掉 "嗯"、"那个" 等口语词
+}
+
+// DefaultConfig 返回默认识别配置，Auth 需要调用方填写
+func DefaultConfig() Config {
+	return Config{
+		Endpoint:   DefaultEndpoint,
+		ResourceID: DefaultResourceID,
+		Audio:      audio.CodecOption{Codec: "pcm", SampleRate: 16000, Channels: 1, BitDepth: 16},
+		EnableITN:  true,
+		EnablePunc: true,
+	}
+}
+
+// Engine 火山引擎流式语音识别，每个 session 使用一条独立的 WebSocket 连接
+type Engine struct {
+	cfg Config
+}
+
+var _ asr.Engine = (*Engine)(nil)
+
+// NewEngine 创建识别引擎，不会立即建立连接
+func NewEngine(cfg Config) (*Engine, error) {
+	if cfg.Auth.AccessKey == "" {
+		return nil, errors.New("accessKey is required")
+	}
+	if cfg.Auth.AppKey == "" {
+		return nil, errors.New("appKey is required")
+	}
+
+	d := DefaultConfig()
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = d.Endpoint
+	}
+	if cfg.ResourceID == "" {
+		cfg.ResourceID = d.ResourceID
+	}
+	if cfg.Audio.Codec == "" {
+		cfg.Audio.Codec = d.Audio.Codec
+	}
+	if cfg.Audio.SampleRate == 0 {
+		cfg.Audio.SampleRate = d.Audio.SampleRate
+	}
+	if cfg.Audio.Channels == 0 {
+		cfg.Audio.Channels = d.Audio.Channels
+	}
+	if cfg.Audio.BitDepth == 0 {
+		cfg.Audio.BitDepth = d.Audio.BitDepth
+	}
+	return &Engine{cfg: cfg}, nil
+}
+
+// Start 建立连接并发送识别参数
+func (e *Engine) Start(ctx context.Context) (asr.Session, error) {
+	header := http.Header{}
+	header.Set("X-Api-App-Key", e.cfg.Auth.AppKey)
+	header.Set("X-Api-Access-Key", e.cfg.Auth.AccessKey)
+	header.Set("X-Api-Resource-Id", e.cfg.ResourceID)
+	header.Set("X-Api-Connect-Id", uuid.New().String())
+
+	client, err := websocket.NewWsClient(ctx, websocket.WSConfig{
+		URL:     e.cfg.Endpoint,
+		Headers: header,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("volc: dial websocket: %w", volctts.HandshakeError(err))
+	}
+
+	s := newSession(client)
+	if err := s.sendRequest(ctx, e.request()); err != nil {
+		s.Close()
+		return nil, err
+	}
+	go s.readLoop()
+	return s, nil
+}
+
+// Close 引擎本身不持有连接，未结束的 session 需要各自 Close
+func (e *Engine) Close() error {
+	return nil
+}
+
+func (e *Engine) request() request {
+	return request{
+		User: requestUser{UID: e.cfg.UID},
+		Audio: requestAudio{
+			Format:  e.cfg.Audio.Codec,
+			Rate:    e.cfg.Audio.SampleRate,
+			Bits:    e.cfg.Audio.BitDepth,
+			Channel: e.cfg.Audio.Channels,
+		},
+		Request: requestOptions{
+			ModelName:      "bigmodel",
+			EnableITN:      e.cfg.EnableITN,
+			EnablePunc:     e.cfg.EnablePunc,
+			EnableDDC:      e.cfg.EnableDDC,
+			ShowUtterances: true,
+			ResultType:     "full",
+		},
+	}
+}
+
+// request 第一包 FullClientRequest 的 payload
+type request struct {
+	User    requestUser    `json:"user"`
+	Audio   requestAudio   `json:"audio"`
+	Request requestOptions `json:"request"`
+}
+
+type requestUser struct {
+	UID string `json:"uid,omitempty"`
+}
+
+type requestAudio struct {
+	Format  string `json:"format"`
+	Rate    int    `json:"rate"`
+	Bits    int    `json:"bits"`
+	Channel int    `json:"channel"`
+}
+
+type requestOptions struct {
+	ModelName      string `json:"model_name"`
+	EnableITN      bool   `json:"enable_itn"`
+	EnablePunc     bool   `json:"enable_punc"`
+	EnableDDC      bool   `json:"enable_ddc"`
+	ShowUtterances bool   `json:"show_utterances"`
+	ResultType     string `json:"result_type"`
+}
+
+// response FullServerResponse 的 payload，时间单位为毫秒
+type response struct {
+	Result struct {
+		Text       string      `json:"text"`
+		Utterances []utterance `json:"utterances"`
+	} `json:"result"`
+}
+
+type utterance struct {
+	Text      string `json:"text"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+	Definite  bool   `json:"definite"`
+	Words     []struct {
+		Text      string `json:"text"`
+		StartTime int64  `json:"start_time"`
+		EndTime   int64  `json:"end_time"`
+	} `json:"words"`
+}
+
+func (u utterance) transcript(final bool) asr.Transcript {
+	t := asr.Transcript{
+		Text:      u.Text,
+		StartTime: seconds(u.StartTime),
+		EndTime:   seconds(u.EndTime),
+		Final:     final,
+	}
+	for _, w := range u.Words {
+		t.Words = append(t.Words, asr.Word{Text: w.Text, StartTime: seconds(w.StartTime), EndTime: seconds(w.EndTime)})
+	}
+	return t
+}
+
+func seconds(ms int64) float64 {
+	return float64(ms) / 1000
+}
@@ -0,0 +1,211 @@
+package volc
+
+import (
+	"ava/internal/asr"
+	"ava/internal/audio"
+	volctts "ava/internal/tts/volc"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+)
+
+// fakeServer 模拟流式识别服务：每收到一包音频返回一次截至当前的结果，
+// 每包音频识别为一个字，最后一包时所有分句确定
+type fakeServer struct {
+	t       *testing.T
+	srv     *httptest.Server
+	words   []string
+	request chan request
+	fail    string // 非空时收到第一包音频后返回该错误
+}
+
+func newFakeServer(t *testing.T, words ...string) *fakeServer {
+	f := &fakeServer{t: t, words: words, request: make(chan request, 1)}
+	f.srv = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.srv.Close)
+	return f
+}
+
+func (f *fakeServer) serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&gorilla.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var text []string
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		msg, err := volctts.NewMessageFromBytes(data)
+		if err != nil {
+			f.t.Errorf("server: unmarshal: %v", err)
+			return
+		}
+
+		if msg.MsgType == volctts.MsgTypeFullClientRequest {
+			var req request
+			_ = json.Unmarshal(msg.Payload, &req)
+			f.request <- req
+			continue
+		}
+
+		if f.fail != "" {
+			reply, _ := volctts.NewMessage(volctts.MsgTypeError, volctts.MsgTypeFlagNoSeq)
+			reply.ErrorCode = 45000000
+			reply.Payload = []byte(f.fail)
+			f.write(conn, reply)
+			return
+		}
+
+		if len(text) < len(f.words) {
+			text = append(text, f.words[len(text)])
+		}
+		last := msg.MsgTypeFlag == volctts.MsgTypeFlagNegativeSeq
+		flag := volctts.MsgTypeFlagPositiveSeq
+		if last {
+			flag = volctts.MsgTypeFlagNegativeSeq
+		}
+		reply, _ := volctts.NewMessage(volctts.MsgTypeFullServerResponse, flag)
+		reply.Sequence = msg.Sequence
+		reply.Payload = f.response(text, last)
+		f.write(conn, reply)
+		if last {
+			return
+		}
+	}
+}
+
+// response 前两个字为一个已确定的分句，之后的字为未确定的分句
+func (f *fakeServer) response(text []string, last bool) []byte {
+	var resp response
+	for start := 0; start < len(text); start += 2 {
+		end := min(start+2, len(text))
+		u := utterance{
+			Text:      strings.Join(text[start:end], ""),
+			StartTime: int64(start * 100),
+			EndTime:   int64(end * 100),
+			Definite:  last || end-start == 2 && end < len(text),
+		}
+		resp.Result.Utterances = append(resp.Result.Utterances, u)
+	}
+	resp.Result.Text = strings.Join(text, "")
+	payload, _ := json.Marshal(resp)
+	return payload
+}
+
+func (f *fakeServer) write(conn *gorilla.Conn, msg *volctts.Message) {
+	data, err := msg.Marshal()
+	if err != nil {
+		f.t.Errorf("server: marshal: %v", err)
+		return
+	}
+	_ = conn.WriteMessage(gorilla.BinaryMessage, data)
+}
+
+func newTestEngine(t *testing.T, f *fakeServer) *Engine {
+	t.Helper()
+	e, err := NewEngine(Config{
+		Endpoint: "ws" + strings.TrimPrefix(f.srv.URL, "http"),
+		Auth:     volctts.AuthConfig{AccessKey: "test-access", AppKey: "test-app"},
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	return e
+}
+
+func collect(t *testing.T, s asr.Session) []asr.Transcript {
+	t.Helper()
+	var result []asr.Transcript
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case tr, ok := <-s.Transcripts():
+			if !ok {
+				return result
+			}
+			result = append(result, tr)
+		case <-timeout:
+			t.Fatal("timeout waiting for transcripts")
+		}
+	}
+}
+
+func TestSession(t *testing.T) {
+	f := newFakeServer(t, "你", "好", "世", "界")
+	e := newTestEngine(t, f)
+
+	ctx := context.Background()
+	s, err := e.Start(ctx)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer s.Close()
+
+	req := <-f.request
+	if req.Audio.Rate != 16000 || req.Audio.Bits != 16 || req.Audio.Channel != 1 || req.Audio.Format != "pcm" {
+		t.Fatalf("unexpected audio config: %+v", req.Audio)
+	}
+
+	frame := make([]byte, 640)
+	for i := 0; i < 4; i++ {
+		if err := s.Write(ctx, audio.Frame{Payload: frame, IsFirst: i == 0, IsLast: i == 3}); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := s.Write(ctx, audio.Frame{Payload: frame}); !errors.Is(err, asr.ErrSessionFinished) {
+		t.Fatalf("expected ErrSessionFinished, got %v", err)
+	}
+
+	var finals []string
+	var partials int
+	for _, tr := range collect(t, s) {
+		if tr.Final {
+			finals = append(finals, tr.Text)
+		} else {
+			partials++
+		}
+	}
+	if strings.Join(finals, "|") != "你好|世界" {
+		t.Fatalf("unexpected finals: %q", finals)
+	}
+	if partials == 0 {
+		t.Fatal("expected partial transcripts")
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSessionServerError(t *testing.T) {
+	f := newFakeServer(t, "你")
+	f.fail = `{"error":"quota exceeded for types: concurrency"}`
+	e := newTestEngine(t, f)
+
+	ctx := context.Background()
+	s, err := e.Start(ctx)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.Write(ctx, audio.Frame{Payload: make([]byte, 640), IsLast: true}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := collect(t, s); len(got) != 0 {
+		t.Fatalf("unexpected transcripts: %+v", got)
+	}
+	if !errors.Is(s.Err(), volctts.ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", s.Err())
+	}
+}
@@ -0,0 +1,232 @@
+package volc
+
+import (
+	"ava/internal/asr"
+	"ava/internal/audio"
+	volctts "ava/internal/tts/volc"
+	"ava/pkg/websocket"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// session 一次识别：客户端按序号发送音频包，服务端每次返回截至当前的完整结果（result_type=full）
+type session struct {
+	client websocket.WsClient
+	ctx    context.Context // Close 时取消
+	cancel context.CancelFunc
+
+	mu       sync.Mutex // 保证音频包按序号发送
+	seq      int32      // 最近一次发送的包序号，第一包 FullClientRequest 为 1
+	finished bool       // 已经发送最后一包
+
+	transcripts chan asr.Transcript
+	errMu       sync.Mutex
+	err         error // transcripts 关闭前写入
+
+	// 以下字段只在 readLoop 中访问
+	finals  int    // 已经输出 Final 的分句数
+	partial string // 最近一次输出的中间结果，避免重复输出
+}
+
+var _ asr.Session = (*session)(nil)
+
+func newSession(client websocket.WsClient) *session {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &session{
+		client:      client,
+		ctx:         ctx,
+		cancel:      cancel,
+		transcripts: make(chan asr.Transcript, 64),
+	}
+}
+
+func (s *session) sendRequest(ctx context.Context, req request) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("volc: marshal asr request: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.send(ctx, volctts.MsgTypeFullClientRequest, volctts.SerializationJSON, false, payload)
+}
+
+// Write 发送一帧音频，frame.IsLast 时以负序号标记最后一包
+func (s *session) Write(ctx context.Context, frame audio.Frame) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx.Err() != nil {
+		return asr.ErrSessionClosed
+	}
+	if s.finished {
+		return asr.ErrSessionFinished
+	}
+	if err := s.send(ctx, volctts.MsgTypeAudioOnlyClient, volctts.SerializationRaw, frame.IsLast, frame.Payload); err != nil {
+		return err
+	}
+	s.finished = frame.IsLast
+	return nil
+}
+
+// send 调用方必须持有 mu
+func (s *session) send(ctx context.Context, msgType volctts.MsgType, serialization volctts.SerializationBits, last bool, payload []byte) error {
+	flag := volctts.MsgTypeFlagPositiveSeq
+	seq := s.seq + 1
+	if last {
+		flag = volctts.MsgTypeFlagNegativeSeq
+	}
+
+	msg, err := volctts.NewMessage(msgType, flag)
+	if err != nil {
+		return err
+	}
+	msg.Serialization = serialization
+	msg.Sequence = seq
+	if last {
+		msg.Sequence = -seq
+	}
+	msg.Payload = payload
+
+	data, err := msg.Marshal()
+	if err != nil {
+		return fmt.Errorf("volc: marshal message: %w", err)
+	}
+	if err := s.client.Send(ctx, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("%w: %v", volctts.ErrConnectionLost, err)
+		}
+		return err
+	}
+	s.seq = seq
+	return nil
+}
+
+func (s *session) Transcripts() <-chan asr.Transcript {
+	return s.transcripts
+}
+
+func (s *session) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+func (s *session) setErr(err error) {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	s.err = err
+}
+
+func (s *session) Close() error {
+	s.cancel()
+	return s.client.Close()
+}
+
+// readLoop 接收服务端结果，直到最后一包、出错或 Close
+func (s *session) readLoop() {
+	defer close(s.transcripts)
+	defer s.client.Close()
+
+	for {
+		data, err := s.client.Recv(s.ctx)
+		if err != nil {
+			if s.ctx.Err() == nil {
+				s.setErr(fmt.Errorf("%w: %v", volctts.ErrConnectionLost, err))
+			}
+			return
+		}
+
+		msg, err := volctts.NewMessageFromBytes(data)
+		if err != nil {
+			s.setErr(fmt.Errorf("volc: unmarshal message: %w", err))
+			return
+		}
+
+		switch msg.MsgType {
+		case volctts.MsgTypeError:
+			s.setErr(volctts.ParseServerError(msg.ErrorCode, msg.Payload))
+			return
+		case volctts.MsgTypeFullServerResponse:
+			last := msg.MsgTypeFlag == volctts.MsgTypeFlagNegativeSeq || msg.MsgTypeFlag == volctts.MsgTypeFlagLastNoSeq
+			resp, err := decodeResponse(msg)
+			if err != nil {
+				s.setErr(err)
+				return
+			}
+			if !s.handle(resp, last) || last {
+				return
+			}
+		}
+	}
+}
+
+// handle 把完整结果转换为增量的 Transcript：确定的分句只输出一次，未确定的分句在文本变化时输出
+// Close 之后返回 false
+func (s *session) handle(resp *response, last bool) bool {
+	utterances := resp.Result.Utterances
+	if len(utterances) == 0 {
+		// 服务端没有返回分句信息时按整段文本输出
+		text := resp.Result.Text
+		if text == "" || (text == s.partial && !last) {
+			return true
+		}
+		s.partial = text
+		return s.emit(asr.Transcript{Text: text, Final: last})
+	}
+
+	for i := s.finals; i < len(utterances); i++ {
+		u := utterances[i]
+		final := u.Definite || last
+		if final {
+			s.finals = i + 1
+			s.partial = ""
+		} else if u.Text == s.partial {
+			continue
+		} else {
+			s.partial = u.Text
+		}
+		if u.Text == "" {
+			continue
+		}
+		if !s.emit(u.transcript(final)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *session) emit(t asr.Transcript) bool {
+	select {
+	case s.transcripts <- t:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+func decodeResponse(msg *volctts.Message) (*response, error) {
+	payload := msg.Payload
+	if msg.Compression == volctts.CompressionGzip {
+		r, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("volc: decompress response: %w", err)
+		}
+		if payload, err = io.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("volc: decompress response: %w", err)
+		}
+	}
+
+	var resp response
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &resp); err != nil {
+			return nil, fmt.Errorf("volc: unmarshal asr response: %w", err)
+		}
+	}
+	return &resp, nil
+}
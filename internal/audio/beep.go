@@ -0,0 +1,158 @@
+package audio
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gopxl/beep"
+)
+
+// pollInterval 源 streamer 暂时没有数据（返回 (0, true)）时的等待间隔
+const pollInterval = 5 * time.Millisecond
+
+// FromBeep 在后台读取 src，按 codec 编码并按 codec.FrameDuration 切成帧写入返回的 Stream
+// src 的采样率必须与 codec.SampleRate 一致（不做重采样）；src 结束时写入 IsLast 帧，
+// src 实现了 Err() error 且返回非 io.EOF 错误时以该错误关闭 Stream。调用方 Close 返回的 Stream 会停止读取 src
+func FromBeep(src beep.Streamer, codec CodecOption, capacity ...int) (Stream, error) {
+	enc, err := NewCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	codec = enc.Option()
+	size, err := codec.FrameSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	c := 0
+	if len(capacity) > 0 {
+		c = capacity[0]
+	}
+	pipe := NewPipe(codec, c)
+	go pumpBeep(pipe, src, enc, size)
+	return pipe, nil
+}
+
+func pumpBeep(pipe *Pipe, src beep.Streamer, enc *Codec, size int) {
+	samples := make([][2]float64, size)
+	filled := 0
+	first := true
+
+	write := func(last bool) bool {
+		frame := Frame{
+			Payload: enc.Encode(nil, samples[:filled]),
+			IsFirst: first,
+			IsLast:  last,
+		}
+		first = false
+		filled = 0
+		return pipe.Write(frame) == nil
+	}
+
+	for {
+		select {
+		case <-pipe.Done():
+			return
+		default:
+		}
+
+		n, ok := src.Stream(samples[filled:])
+		filled += n
+		if filled == size {
+			if !write(false) {
+				return
+			}
+		}
+		if !ok {
+			if err := sourceErr(src); err != nil {
+				pipe.CloseWithError(err)
+				return
+			}
+			write(true)
+			return
+		}
+		if n == 0 {
+			time.Sleep(pollInterval)
+		}
+	}
+}
+
+// sourceErr 返回 beep.Streamer 的结束原因，正常结束返回 nil
+func sourceErr(src beep.Streamer) error {
+	err := src.Err()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// BeepStreamer 把 Stream 中的帧解码为 beep.Streamer
+// Stream 暂时没有数据时 Stream 方法会阻塞等待，适合离线处理或有独立缓冲的播放端
+type BeepStreamer struct {
+	src   Stream
+	codec CodecOption
+	dec   *Codec
+
+	mu      sync.Mutex
+	pending []byte // 上一帧未读完的数据
+	done    bool
+	err     error
+}
+
+// ToBeep 把 src 包装为 beep.Streamer，codec 描述 src 中帧的格式
+func ToBeep(src Stream, codec CodecOption) (*BeepStreamer, error) {
+	dec, err := NewCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	return &BeepStreamer{src: src, codec: dec.Option(), dec: dec}, nil
+}
+
+// Format 返回解码后的音频格式
+func (b *BeepStreamer) Format() beep.Format {
+	return beep.Format{
+		SampleRate:  beep.SampleRate(b.codec.SampleRate),
+		NumChannels: b.codec.Channels,
+		Precision:   b.codec.BitDepth / 8,
+	}
+}
+
+func (b *BeepStreamer) Stream(samples [][2]float64) (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	size := b.codec.BytesPerSample()
+	n := 0
+	for n < len(samples) {
+		if len(b.pending) < size {
+			if b.done {
+				break
+			}
+			frame, err := b.src.Read()
+			if err != nil {
+				b.done = true
+				if !errors.Is(err, io.EOF) {
+					b.err = err
+				}
+				break
+			}
+			b.pending = append(b.pending, frame.Payload...)
+			if frame.IsLast {
+				b.done = true
+			}
+			continue
+		}
+		decoded := b.dec.Decode(samples[n:], b.pending)
+		b.pending = b.pending[decoded*size:]
+		n += decoded
+	}
+	return n, n > 0 || !b.done
+}
+
+func (b *BeepStreamer) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.err
+}
@@ -0,0 +1,213 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+// 内置的编码名称，用于 CodecOption.Codec
+const (
+	CodecPCM   = "pcm"   // 线性 PCM 小端，8 位为无符号，16/24/32 位为有符号整数
+	CodecFloat = "float" // 32 位 IEEE 754 浮点 PCM 小端
+	CodecPCMU  = "pcmu"  // G.711 μ-law，8 位
+	CodecPCMA  = "pcma"  // G.711 A-law，8 位
+)
+
+// SampleCodec 单个采样值（一个声道）的编解码
+type SampleCodec interface {
+	Width() int                 // 每个采样值的字节数
+	Decode(b []byte) float64    // 解码为 [-1, 1]
+	Encode(b []byte, v float64) // 编码 [-1, 1] 的采样值，超出范围的值会被截断
+	Silence() byte              // 静音对应的字节值，用于补齐
+}
+
+// staticPayloadTypes RFC 3551 为编码分配的 RTP 静态负载类型
+var staticPayloadTypes = map[string]uint8{
+	CodecPCMU: 0,
+	CodecPCMA: 8,
+}
+
+type codecKey struct {
+	name     string
+	bitDepth int
+}
+
+var (
+	codecMu       sync.RWMutex
+	codecs        = map[codecKey]SampleCodec{}
+	codecDefaults = map[string]int{} // 编码名称 -> 第一个注册的位深，用于 CodecOption.WithDefaults
+)
+
+func init() {
+	RegisterCodec(CodecPCM, 16, linear16{})
+	RegisterCodec(CodecPCM, 8, linear8{})
+	RegisterCodec(CodecPCM, 24, linear24{})
+	RegisterCodec(CodecPCM, 32, linear32{})
+	RegisterCodec(CodecFloat, 32, float32LE{})
+	RegisterCodec(CodecPCMU, 8, ulaw{})
+	RegisterCodec(CodecPCMA, 8, alaw{})
+}
+
+// RegisterCodec 注册编码 name 在 bitDepth 位深下的实现，名称不区分大小写，重复注册会覆盖
+func RegisterCodec(name string, bitDepth int, c SampleCodec) {
+	name = strings.ToLower(name)
+	codecMu.Lock()
+	defer codecMu.Unlock()
+	codecs[codecKey{name, bitDepth}] = c
+	if _, ok := codecDefaults[name]; !ok {
+		codecDefaults[name] = bitDepth
+	}
+}
+
+// LookupCodec 查找已注册的编码实现
+func LookupCodec(name string, bitDepth int) (SampleCodec, bool) {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	c, ok := codecs[codecKey{strings.ToLower(name), bitDepth}]
+	return c, ok
+}
+
+// defaultBitDepth 返回编码的默认位深，未注册时返回 0
+func defaultBitDepth(name string) int {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	return codecDefaults[strings.ToLower(name)]
+}
+
+// Codec 按 CodecOption 在字节和双声道 [-1, 1] 采样之间转换整段数据
+type Codec struct {
+	option CodecOption
+	sample SampleCodec
+}
+
+// NewCodec 根据 option 创建 Codec，支持单/双声道
+func NewCodec(option CodecOption) (*Codec, error) {
+	option = option.WithDefaults()
+	sample, ok := LookupCodec(option.Codec, option.BitDepth)
+	if !ok {
+		return nil, fmt.Errorf("audio: unsupported codec %s/%d", option.Codec, option.BitDepth)
+	}
+	if option.Channels != 1 && option.Channels != 2 {
+		return nil, fmt.Errorf("audio: unsupported channels %d", option.Channels)
+	}
+	return &Codec{option: option, sample: sample}, nil
+}
+
+// Option 返回补全默认值后的 CodecOption
+func (c *Codec) Option() CodecOption {
+	return c.option
+}
+
+// Decode 把数据解码为双声道采样，单声道复制到两个声道，返回解码的采样点数，不完整的尾部被忽略
+func (c *Codec) Decode(samples [][2]float64, payload []byte) int {
+	width := c.sample.Width()
+	size := width * c.option.Channels
+	n := min(len(samples), len(payload)/size)
+	for i := 0; i < n; i++ {
+		p := payload[i*size:]
+		l := c.sample.Decode(p)
+		r := l
+		if c.option.Channels == 2 {
+			r = c.sample.Decode(p[width:])
+		}
+		samples[i] = [2]float64{l, r}
+	}
+	return n
+}
+
+// Encode 把双声道采样编码后追加到 dst，单声道取两个声道的平均
+func (c *Codec) Encode(dst []byte, samples [][2]float64) []byte {
+	width := c.sample.Width()
+	size := width * c.option.Channels
+	start := len(dst)
+	dst = append(dst, make([]byte, len(samples)*size)...)
+	for i, s := range samples {
+		p := dst[start+i*size:]
+		if c.option.Channels == 1 {
+			c.sample.Encode(p, (s[0]+s[1])/2)
+			continue
+		}
+		c.sample.Encode(p, s[0])
+		c.sample.Encode(p[width:], s[1])
+	}
+	return dst
+}
+
+// Silence 用静音填充 b
+func (c *Codec) Silence(b []byte) {
+	v := c.sample.Silence()
+	for i := range b {
+		b[i] = v
+	}
+}
+
+// Transcode 把 src 格式的数据转换为 dst 格式（采样率必须一致，只转换编码、位深和声道数）
+func Transcode(payload []byte, src, dst *Codec) []byte {
+	samples := make([][2]float64, len(payload)/src.option.BytesPerSample())
+	n := src.Decode(samples, payload)
+	return dst.Encode(nil, samples[:n])
+}
+
+type linear8 struct{}
+
+func (linear8) Width() int    { return 1 }
+func (linear8) Silence() byte { return 0x80 }
+func (linear8) Decode(b []byte) float64 {
+	return (float64(b[0]) - 128) / 128
+}
+func (linear8) Encode(b []byte, v float64) {
+	b[0] = uint8(math.Round(clamp(v)*127) + 128)
+}
+
+type linear16 struct{}
+
+func (linear16) Width() int    { return 2 }
+func (linear16) Silence() byte { return 0 }
+func (linear16) Decode(b []byte) float64 {
+	return float64(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+}
+func (linear16) Encode(b []byte, v float64) {
+	binary.LittleEndian.PutUint16(b, uint16(int16(math.Round(clamp(v)*math.MaxInt16))))
+}
+
+type linear24 struct{}
+
+func (linear24) Width() int    { return 3 }
+func (linear24) Silence() byte { return 0 }
+func (linear24) Decode(b []byte) float64 {
+	v := int32(b[0]) | int32(b[1])<<8 | int32(int8(b[2]))<<16
+	return float64(v) / (1 << 23)
+}
+func (linear24) Encode(b []byte, v float64) {
+	s := int32(math.Round(clamp(v) * (1<<23 - 1)))
+	b[0], b[1], b[2] = byte(s), byte(s>>8), byte(s>>16)
+}
+
+type linear32 struct{}
+
+func (linear32) Width() int    { return 4 }
+func (linear32) Silence() byte { return 0 }
+func (linear32) Decode(b []byte) float64 {
+	return float64(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+}
+func (linear32) Encode(b []byte, v float64) {
+	binary.LittleEndian.PutUint32(b, uint32(int32(math.Round(clamp(v)*math.MaxInt32))))
+}
+
+type float32LE struct{}
+
+func (float32LE) Width() int    { return 4 }
+func (float32LE) Silence() byte { return 0 }
+func (float32LE) Decode(b []byte) float64 {
+	return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+}
+func (float32LE) Encode(b []byte, v float64) {
+	binary.LittleEndian.PutUint32(b, math.Float32bits(float32(clamp(v))))
+}
+
+func clamp(v float64) float64 {
+	return math.Max(-1, math.Min(1, v))
+}
@@ -0,0 +1,82 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	tests := []struct {
+		codec     string
+		bitDepth  int
+		tolerance float64
+	}{
+		{CodecPCM, 8, 1.0 / 64},
+		{CodecPCM, 16, 1e-4},
+		{CodecPCM, 24, 1e-6},
+		{CodecPCM, 32, 1e-8},
+		{CodecFloat, 32, 1e-6},
+		{CodecPCMU, 0, 0.03}, // G.711 对数量化，大幅度时误差约 3%
+		{CodecPCMA, 0, 0.03},
+	}
+	in := [][2]float64{{0, 0}, {0.5, -0.5}, {-0.25, 0.75}, {0.999, -0.999}, {0.01, -0.01}}
+	for _, tt := range tests {
+		c, err := NewCodec(CodecOption{Codec: tt.codec, BitDepth: tt.bitDepth, Channels: 2})
+		if err != nil {
+			t.Fatalf("%s/%d: %v", tt.codec, tt.bitDepth, err)
+		}
+		payload := c.Encode(nil, in)
+		if want := len(in) * c.Option().BytesPerSample(); len(payload) != want {
+			t.Fatalf("%s/%d: payload size %d, want %d", tt.codec, tt.bitDepth, len(payload), want)
+		}
+		out := make([][2]float64, len(in))
+		if n := c.Decode(out, payload); n != len(in) {
+			t.Fatalf("%s/%d: decoded %d samples", tt.codec, tt.bitDepth, n)
+		}
+		for i := range in {
+			for ch := 0; ch < 2; ch++ {
+				if d := math.Abs(out[i][ch] - in[i][ch]); d > tt.tolerance {
+					t.Fatalf("%s/%d: sample %d = %v, want %v", tt.codec, tt.bitDepth, i, out[i][ch], in[i][ch])
+				}
+			}
+		}
+	}
+
+	if _, err := NewCodec(CodecOption{Codec: CodecPCMU, BitDepth: 16}); err == nil {
+		t.Fatal("expected error for 16-bit pcmu")
+	}
+}
+
+func TestG711(t *testing.T) {
+	// ITU-T G.711 的静音码字
+	if got := LinearToULaw(0); got != 0xff {
+		t.Fatalf("ulaw(0) = %#x", got)
+	}
+	if got := LinearToALaw(0); got != 0xd5 {
+		t.Fatalf("alaw(0) = %#x", got)
+	}
+	// 所有码字解码后重新编码应得到原码字（μ-law 的 0x7f 为负零，编码为 0xff）
+	for i := 0; i < 256; i++ {
+		u := byte(i)
+		if got := LinearToULaw(ULawToLinear(u)); got != u && u != 0x7f {
+			t.Fatalf("ulaw %#x -> %d -> %#x", u, ULawToLinear(u), got)
+		}
+		if got := LinearToALaw(ALawToLinear(u)); got != u {
+			t.Fatalf("alaw %#x -> %d -> %#x", u, ALawToLinear(u), got)
+		}
+	}
+}
+
+func TestCodecDefaults(t *testing.T) {
+	c := CodecOption{Codec: CodecPCMU}.WithDefaults()
+	if c.BitDepth != 8 || c.PayloadType != 0 {
+		t.Fatalf("unexpected pcmu defaults: %+v", c)
+	}
+	c = CodecOption{Codec: CodecPCMA}.WithDefaults()
+	if c.BitDepth != 8 || c.PayloadType != 8 {
+		t.Fatalf("unexpected pcma defaults: %+v", c)
+	}
+	if c := (CodecOption{}).WithDefaults(); c != DefaultCodecOption() {
+		t.Fatalf("unexpected defaults: %+v", c)
+	}
+}
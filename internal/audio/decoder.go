@@ -0,0 +1,191 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/gopxl/beep"
+)
+
+// Decoder 压缩音频（MP3、Ogg/Opus 等）的增量解码器，输入可以按任意边界切分
+// 解码出的样本通过创建时传入的 emit 回调输出，回调可能在 Write 中同步调用，也可能在解码器的 goroutine 中调用
+type Decoder interface {
+	Write(p []byte) error // 写入压缩数据
+	Close() error         // 输入结束：解码剩余数据并等待 emit 全部返回，可以重复调用
+}
+
+// NewDecoderFunc 创建解码器，sampleRate 和 channels 为期望的输出格式（服务端按请求的参数编码），
+// 解码出的音频采样率不同时解码器应自行重采样
+type NewDecoderFunc func(sampleRate, channels int, emit func(samples [][2]float64)) (Decoder, error)
+
+var (
+	decoderMu sync.RWMutex
+	decoders  = map[string]NewDecoderFunc{}
+)
+
+// RegisterDecoder 注册编码 encoding（如 "mp3"、"ogg_opus"）的解码器，名称不区分大小写，重复注册会覆盖
+// 本包不依赖具体的 MP3 / Opus 实现，由使用方注册，如 RegisterDecoder("mp3", BeepDecoder(mp3.Decode))；tts/volc 包会注册 mp3 和 ogg_opus
+func RegisterDecoder(encoding string, fn NewDecoderFunc) {
+	decoderMu.Lock()
+	defer decoderMu.Unlock()
+	decoders[strings.ToLower(encoding)] = fn
+}
+
+// HasDecoder 是否注册了 encoding 的解码器
+func HasDecoder(encoding string) bool {
+	decoderMu.RLock()
+	defer decoderMu.RUnlock()
+	_, ok := decoders[strings.ToLower(encoding)]
+	return ok
+}
+
+// NewDecoder 使用注册的解码器创建 Decoder
+func NewDecoder(encoding string, sampleRate, channels int, emit func(samples [][2]float64)) (Decoder, error) {
+	decoderMu.RLock()
+	fn, ok := decoders[strings.ToLower(encoding)]
+	decoderMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("audio: no decoder registered for %q", encoding)
+	}
+	return fn(sampleRate, channels, emit)
+}
+
+// BeepDecodeFunc beep 解码函数的签名，如 mp3.Decode、vorbis.Decode
+type BeepDecodeFunc func(rc io.ReadCloser) (beep.StreamSeekCloser, beep.Format, error)
+
+// BeepDecoder 把基于 io.Reader 的 beep 解码器包装为增量 Decoder
+// 解码在独立的 goroutine 中进行，Write 不会阻塞；音频采样率与期望不同时自动重采样
+func BeepDecoder(decode BeepDecodeFunc) NewDecoderFunc {
+	return func(sampleRate, channels int, emit func(samples [][2]float64)) (Decoder, error) {
+		if sampleRate <= 0 {
+			return nil, fmt.Errorf("audio: invalid sample rate %d", sampleRate)
+		}
+		d := &beepDecoder{
+			input: newBytePipe(),
+			done:  make(chan struct{}),
+		}
+		go d.run(decode, beep.SampleRate(sampleRate), emit)
+		return d, nil
+	}
+}
+
+type beepDecoder struct {
+	input *bytePipe
+	done  chan struct{}
+	err   error // run 退出后只读
+}
+
+func (d *beepDecoder) run(decode BeepDecodeFunc, rate beep.SampleRate, emit func([][2]float64)) {
+	defer close(d.done)
+	// 解码提前结束时丢弃之后写入的数据，避免 Write 的数据无限堆积
+	defer d.input.CloseRead()
+
+	s, format, err := decode(d.input)
+	if err != nil {
+		d.err = fmt.Errorf("audio: decode: %w", err)
+		return
+	}
+	defer s.Close()
+
+	var src beep.Streamer = s
+	if format.SampleRate != rate {
+		if src, err = Resample(s, format.SampleRate, rate); err != nil {
+			d.err = err
+			return
+		}
+	}
+	buf := make([][2]float64, 1024)
+	for {
+		n, ok := src.Stream(buf)
+		if n > 0 {
+			out := make([][2]float64, n)
+			copy(out, buf[:n])
+			emit(out)
+		}
+		if !ok {
+			break
+		}
+	}
+	if err := src.Err(); err != nil && !errors.Is(err, io.EOF) {
+		d.err = fmt.Errorf("audio: decode: %w", err)
+	}
+}
+
+func (d *beepDecoder) Write(p []byte) error {
+	select {
+	case <-d.done:
+		if d.err != nil {
+			return d.err
+		}
+		return ErrStreamClosed
+	default:
+	}
+	d.input.Write(p)
+	return nil
+}
+
+func (d *beepDecoder) Close() error {
+	d.input.Close()
+	<-d.done
+	return d.err
+}
+
+// bytePipe 无界的字节管道：Write 不阻塞，Read 在没有数据时阻塞到写入或关闭
+type bytePipe struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buf    []byte
+	closed bool // 写入端已关闭
+	dead   bool // 读取端已关闭，丢弃写入
+}
+
+func newBytePipe() *bytePipe {
+	p := &bytePipe{}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+func (p *bytePipe) Write(b []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed || p.dead {
+		return
+	}
+	p.buf = append(p.buf, b...)
+	p.cond.Broadcast()
+}
+
+func (p *bytePipe) Read(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.buf) == 0 && !p.closed && !p.dead {
+		p.cond.Wait()
+	}
+	if len(p.buf) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, p.buf)
+	p.buf = p.buf[n:]
+	return n, nil
+}
+
+// Close 关闭写入端，缓冲的数据读完后 Read 返回 io.EOF
+func (p *bytePipe) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	p.cond.Broadcast()
+	return nil
+}
+
+// CloseRead 关闭读取端并丢弃缓冲
+func (p *bytePipe) CloseRead() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dead = true
+	p.buf = nil
+	p.cond.Broadcast()
+}
@@ -0,0 +1,68 @@
+package audio
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/gopxl/beep"
+)
+
+// rawDecode 模拟 beep 解码器：按 8kHz 单声道 PCM16 读取 rc
+func rawDecode(rc io.ReadCloser) (beep.StreamSeekCloser, beep.Format, error) {
+	format := beep.Format{SampleRate: 8000, NumChannels: 1, Precision: 2}
+	return &rawStreamer{rc: rc}, format, nil
+}
+
+type rawStreamer struct {
+	rc  io.ReadCloser
+	err error
+}
+
+func (r *rawStreamer) Stream(samples [][2]float64) (int, bool) {
+	buf := make([]byte, len(samples)*2)
+	n, err := io.ReadAtLeast(r.rc, buf, 2)
+	if err != nil && n < 2 {
+		if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			r.err = err
+		}
+		return 0, false
+	}
+	return (&Codec{option: CodecOption{Channels: 1, BitDepth: 16}, sample: linear16{}}).Decode(samples, buf[:n/2*2]), true
+}
+
+func (r *rawStreamer) Err() error     { return r.err }
+func (r *rawStreamer) Len() int       { return 0 }
+func (r *rawStreamer) Position() int  { return 0 }
+func (r *rawStreamer) Seek(int) error { return errors.New("not seekable") }
+func (r *rawStreamer) Close() error   { return r.rc.Close() }
+
+func TestBeepDecoder(t *testing.T) {
+	RegisterDecoder("test_raw", BeepDecoder(rawDecode))
+	if !HasDecoder("TEST_RAW") {
+		t.Fatal("decoder not registered")
+	}
+
+	total := 0
+	// 期望 16kHz 输出，8kHz 的解码结果会被重采样
+	dec, err := NewDecoder("test_raw", 16000, 1, func(samples [][2]float64) { total += len(samples) })
+	if err != nil {
+		t.Fatalf("new decoder: %v", err)
+	}
+	payload := make([]byte, 1600) // 800 个 8kHz 样本
+	for i := 0; i < len(payload); i += 333 {
+		if err := dec.Write(payload[i:min(i+333, len(payload))]); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := dec.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if total != 1600 {
+		t.Fatalf("unexpected sample count: %d", total)
+	}
+
+	if _, err := NewDecoder("mp3", 16000, 1, func([][2]float64) {}); err == nil {
+		t.Fatal("expected error for unregistered decoder")
+	}
+}
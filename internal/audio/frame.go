@@ -1,5 +1,11 @@
 package audio
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 type Frame struct {
 	Payload []byte
 	IsFirst bool
@@ -14,3 +20,87 @@ type CodecOption struct {
 	FrameDuration string `json:"frameDuration" default:"20ms"`
 	PayloadType   uint8  `json:"payloadType" default:"1"`
 }
+
+// DefaultCodecOption 返回与 struct tag 中默认值一致的配置
+func DefaultCodecOption() CodecOption {
+	return CodecOption{
+		Codec:         "pcm",
+		SampleRate:    16000,
+		Channels:      1,
+		BitDepth:      16,
+		FrameDuration: "20ms",
+		PayloadType:   1,
+	}
+}
+
+// WithDefaults 返回零值字段填充默认值后的副本
+func (c CodecOption) WithDefaults() CodecOption {
+	d := DefaultCodecOption()
+	if c.Codec == "" {
+		c.Codec = d.Codec
+	}
+	if c.SampleRate <= 0 {
+		c.SampleRate = d.SampleRate
+	}
+	if c.Channels <= 0 {
+		c.Channels = d.Channels
+	}
+	if c.BitDepth <= 0 {
+		// G.711 等编码只有一种位深
+		c.BitDepth = defaultBitDepth(c.Codec)
+		if c.BitDepth <= 0 {
+			c.BitDepth = d.BitDepth
+		}
+	}
+	if c.FrameDuration == "" {
+		c.FrameDuration = d.FrameDuration
+	}
+	if c.PayloadType == 0 {
+		// G.711 使用 RFC 3551 的静态负载类型（PCMU 为 0）
+		if pt, ok := staticPayloadTypes[strings.ToLower(c.Codec)]; ok {
+			c.PayloadType = pt
+		} else {
+			c.PayloadType = d.PayloadType
+		}
+	}
+	return c
+}
+
+// FrameDurationValue 解析 FrameDuration，如 "20ms"
+func (c CodecOption) FrameDurationValue() (time.Duration, error) {
+	d, err := time.ParseDuration(c.FrameDuration)
+	if err != nil {
+		return 0, fmt.Errorf("audio: frame duration %q: %w", c.FrameDuration, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("audio: frame duration %q must be positive", c.FrameDuration)
+	}
+	return d, nil
+}
+
+// FrameSamples 返回每帧的采样点数（每个声道）
+func (c CodecOption) FrameSamples() (int, error) {
+	d, err := c.FrameDurationValue()
+	if err != nil {
+		return 0, err
+	}
+	n := int(int64(c.SampleRate) * int64(d) / int64(time.Second))
+	if n <= 0 {
+		return 0, fmt.Errorf("audio: frame duration %s too short", d)
+	}
+	return n, nil
+}
+
+// BytesPerSample 一个采样点（所有声道）占用的字节数
+func (c CodecOption) BytesPerSample() int {
+	return c.BitDepth / 8 * c.Channels
+}
+
+// Duration 返回 n 字节 PCM 数据的时长
+func (c CodecOption) Duration(n int) time.Duration {
+	size := c.BytesPerSample()
+	if size <= 0 || c.SampleRate <= 0 {
+		return 0
+	}
+	return time.Duration(n/size) * time.Second / time.Duration(c.SampleRate)
+}
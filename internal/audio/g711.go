@@ -0,0 +1,105 @@
+package audio
+
+// G.711 μ-law / A-law 编解码（ITU-T G.711），按 14/13 位线性值转换
+
+const (
+	ulawBias = 0x84
+	ulawClip = 32635
+)
+
+type ulaw struct{}
+
+func (ulaw) Width() int    { return 1 }
+func (ulaw) Silence() byte { return 0xff }
+func (ulaw) Decode(b []byte) float64 {
+	return float64(ULawToLinear(b[0])) / (1 << 15)
+}
+func (ulaw) Encode(b []byte, v float64) {
+	b[0] = LinearToULaw(int16(clamp(v) * 32767))
+}
+
+type alaw struct{}
+
+func (alaw) Width() int    { return 1 }
+func (alaw) Silence() byte { return 0xd5 }
+func (alaw) Decode(b []byte) float64 {
+	return float64(ALawToLinear(b[0])) / (1 << 15)
+}
+func (alaw) Encode(b []byte, v float64) {
+	b[0] = LinearToALaw(int16(clamp(v) * 32767))
+}
+
+// LinearToULaw 把 16 位线性采样编码为 μ-law
+func LinearToULaw(sample int16) byte {
+	s := int(sample)
+	sign := 0
+	if s < 0 {
+		s = -s
+		sign = 0x80
+	}
+	if s > ulawClip {
+		s = ulawClip
+	}
+	s += ulawBias
+	exponent := 7
+	for mask := 0x4000; s&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
+	mantissa := (s >> (exponent + 3)) & 0x0f
+	return ^byte(sign | exponent<<4 | mantissa)
+}
+
+// ULawToLinear 把 μ-law 解码为 16 位线性采样
+func ULawToLinear(u byte) int16 {
+	u = ^u
+	exponent := int(u>>4) & 0x07
+	mantissa := int(u & 0x0f)
+	s := ((mantissa << 3) + ulawBias) << exponent
+	s -= ulawBias
+	if u&0x80 != 0 {
+		return int16(-s)
+	}
+	return int16(s)
+}
+
+// aLawSegmentEnd A-law 各段 13 位线性值的上界
+var aLawSegmentEnd = [8]int{0x1f, 0x3f, 0x7f, 0xff, 0x1ff, 0x3ff, 0x7ff, 0xfff}
+
+// LinearToALaw 把 16 位线性采样编码为 A-law
+func LinearToALaw(sample int16) byte {
+	s := int(sample) >> 3 // A-law 使用 13 位
+	mask := byte(0xd5)
+	if s < 0 {
+		s = -s - 1
+		mask = 0x55
+	}
+	seg := 0
+	for seg < len(aLawSegmentEnd) && s > aLawSegmentEnd[seg] {
+		seg++
+	}
+	if seg >= len(aLawSegmentEnd) {
+		return 0x7f ^ mask
+	}
+	shift := seg
+	if seg < 2 {
+		shift = 1
+	}
+	return byte(seg<<4|(s>>shift)&0x0f) ^ mask
+}
+
+// ALawToLinear 把 A-law 解码为 16 位线性采样
+func ALawToLinear(a byte) int16 {
+	a ^= 0x55
+	exponent := int(a>>4) & 0x07
+	mantissa := int(a & 0x0f)
+	var s int
+	if exponent == 0 {
+		s = mantissa<<4 + 8
+	} else {
+		s = (mantissa<<4 + 0x108) << (exponent - 1)
+	}
+	if a&0x80 != 0 {
+		return int16(s)
+	}
+	return int16(-s)
+}
@@ -0,0 +1,256 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const oggHeaderSize = 27
+
+var (
+	oggCapture = []byte("OggS")
+	oggCRC     = makeOggCRCTable()
+
+	// ErrInvalidOggPage Ogg 页格式错误或校验失败
+	ErrInvalidOggPage = errors.New("audio: invalid ogg page")
+)
+
+// OggDemuxer 增量解析 Ogg 页（RFC 3533）并拼出完整的逻辑包，只支持单个逻辑流
+// 数据可以按任意边界写入；页头之前的垃圾数据会被跳过以重新同步，校验失败的页被丢弃
+type OggDemuxer struct {
+	buf     []byte
+	packet  []byte // 跨页未结束的包
+	partial bool
+	dropped int // 校验失败丢弃的页数
+}
+
+// Write 写入数据，返回本次拼出的完整包
+func (d *OggDemuxer) Write(p []byte) [][]byte {
+	d.buf = append(d.buf, p...)
+	var packets [][]byte
+	for {
+		i := bytes.Index(d.buf, oggCapture)
+		if i < 0 {
+			// 保留可能是页头开头的尾部
+			if keep := len(oggCapture) - 1; len(d.buf) > keep {
+				d.buf = append(d.buf[:0], d.buf[len(d.buf)-keep:]...)
+			}
+			return packets
+		}
+		d.buf = d.buf[i:]
+
+		size, err := oggPageSize(d.buf)
+		if err != nil {
+			// 不是合法页头，跳过这个 "OggS" 继续查找
+			d.buf = d.buf[1:]
+			continue
+		}
+		if size == 0 || len(d.buf) < size {
+			return packets // 等待更多数据
+		}
+		page := d.buf[:size]
+		d.buf = d.buf[size:]
+		if !oggCheckCRC(page) {
+			d.dropped++
+			d.packet, d.partial = nil, false
+			continue
+		}
+		packets = append(packets, d.page(page)...)
+	}
+}
+
+// Dropped 返回校验失败被丢弃的页数
+func (d *OggDemuxer) Dropped() int {
+	return d.dropped
+}
+
+// page 按分段表拆出页中的包
+func (d *OggDemuxer) page(page []byte) [][]byte {
+	continued := page[5]&0x01 != 0
+	if continued != d.partial {
+		// 不连续：丢弃上一页未完成的包
+		d.packet = nil
+	}
+
+	segments := int(page[26])
+	lacing := page[oggHeaderSize : oggHeaderSize+segments]
+	data := page[oggHeaderSize+segments:]
+
+	var packets [][]byte
+	// 丢失了包的开头（如从中途开始接收），跳过第一个包的剩余部分
+	skip := continued && !d.partial
+	for _, l := range lacing {
+		seg := data[:l]
+		data = data[l:]
+		if !skip {
+			d.packet = append(d.packet, seg...)
+		}
+		if l < 255 {
+			if !skip {
+				packets = append(packets, d.packet)
+			}
+			d.packet = nil
+			skip = false
+		}
+	}
+	// 最后一个分段为 255 时包延续到下一页
+	d.partial = len(lacing) > 0 && lacing[len(lacing)-1] == 255 && !skip
+	return packets
+}
+
+// oggPageSize 返回页的总长度，页头不完整时返回 0
+func oggPageSize(b []byte) (int, error) {
+	if len(b) < oggHeaderSize {
+		return 0, nil
+	}
+	if b[4] != 0 {
+		return 0, fmt.Errorf("%w: version %d", ErrInvalidOggPage, b[4])
+	}
+	segments := int(b[26])
+	if len(b) < oggHeaderSize+segments {
+		return 0, nil
+	}
+	size := oggHeaderSize + segments
+	for _, l := range b[oggHeaderSize : oggHeaderSize+segments] {
+		size += int(l)
+	}
+	return size, nil
+}
+
+func oggCheckCRC(page []byte) bool {
+	want := binary.LittleEndian.Uint32(page[22:])
+	var crc uint32
+	for i, b := range page {
+		if i >= 22 && i < 26 {
+			b = 0 // 计算时校验字段按 0 处理
+		}
+		crc = crc<<8 ^ oggCRC[byte(crc>>24)^b]
+	}
+	return crc == want
+}
+
+func makeOggCRCTable() *[256]uint32 {
+	var t [256]uint32
+	for i := range t {
+		r := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if r&0x80000000 != 0 {
+				r = r<<1 ^ 0x04c11db7
+			} else {
+				r <<= 1
+			}
+		}
+		t[i] = r
+	}
+	return &t
+}
+
+// PacketDecoder 把一个压缩包解码为样本，如 Opus 解码器
+type PacketDecoder interface {
+	DecodePacket(packet []byte) ([][2]float64, error)
+}
+
+// OpusHead Ogg/Opus 的标识头（RFC 7845 5.1）
+type OpusHead struct {
+	Channels        int
+	PreSkip         int // 开头需要丢弃的 48kHz 样本数
+	InputSampleRate int // 编码前的原始采样率，仅供参考
+}
+
+// ParseOpusHead 解析 OpusHead 包
+func ParseOpusHead(packet []byte) (OpusHead, error) {
+	if len(packet) < 19 || !bytes.HasPrefix(packet, []byte("OpusHead")) {
+		return OpusHead{}, errors.New("audio: invalid OpusHead")
+	}
+	return OpusHead{
+		Channels:        int(packet[9]),
+		PreSkip:         int(binary.LittleEndian.Uint16(packet[10:])),
+		InputSampleRate: int(binary.LittleEndian.Uint32(packet[12:])),
+	}, nil
+}
+
+// OggOpusDecoder 返回 Ogg/Opus 的 NewDecoderFunc：解析 Ogg 页和 Opus 头，音频包交给 newOpus 创建的解码器
+// Opus 本身的解码由 newOpus 提供（如 pion/opus、libopus），本包不内置；newOpus 按 sampleRate 输出（Opus 支持 8/12/16/24/48kHz）
+func OggOpusDecoder(newOpus func(sampleRate, channels int) (PacketDecoder, error)) NewDecoderFunc {
+	return func(sampleRate, channels int, emit func(samples [][2]float64)) (Decoder, error) {
+		if sampleRate <= 0 {
+			return nil, fmt.Errorf("audio: invalid sample rate %d", sampleRate)
+		}
+		return &oggOpusDecoder{
+			sampleRate: sampleRate,
+			channels:   channels,
+			newOpus:    newOpus,
+			emit:       emit,
+		}, nil
+	}
+}
+
+type oggOpusDecoder struct {
+	sampleRate int
+	channels   int
+	newOpus    func(sampleRate, channels int) (PacketDecoder, error)
+	emit       func([][2]float64)
+
+	mu      sync.Mutex
+	demux   OggDemuxer
+	opus    PacketDecoder
+	skip    int // 还需要丢弃的输出样本数（pre-skip）
+	headers int // 已收到的头部包数（OpusHead、OpusTags）
+}
+
+func (d *oggOpusDecoder) Write(p []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, packet := range d.demux.Write(p) {
+		if err := d.packet(packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *oggOpusDecoder) packet(packet []byte) error {
+	switch {
+	case d.headers == 0:
+		head, err := ParseOpusHead(packet)
+		if err != nil {
+			return err
+		}
+		channels := d.channels
+		if channels <= 0 {
+			channels = head.Channels
+		}
+		if d.opus, err = d.newOpus(d.sampleRate, channels); err != nil {
+			return fmt.Errorf("audio: opus: %w", err)
+		}
+		d.skip = head.PreSkip * d.sampleRate / 48000
+		d.headers++
+		return nil
+	case d.headers == 1:
+		// OpusTags，不需要
+		d.headers++
+		return nil
+	}
+
+	samples, err := d.opus.DecodePacket(packet)
+	if err != nil {
+		return fmt.Errorf("audio: opus: %w", err)
+	}
+	if d.skip > 0 {
+		n := min(d.skip, len(samples))
+		samples = samples[n:]
+		d.skip -= n
+	}
+	if len(samples) > 0 {
+		d.emit(samples)
+	}
+	return nil
+}
+
+// Close Ogg/Opus 的解码是同步的，没有需要等待的数据
+func (d *oggOpusDecoder) Close() error {
+	return nil
+}
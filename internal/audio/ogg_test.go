@@ -0,0 +1,97 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// oggPage 构造一个 Ogg 页，lacing 为分段表
+func oggPage(seq uint32, continued bool, lacing []byte, data []byte) []byte {
+	page := make([]byte, oggHeaderSize, oggHeaderSize+len(lacing)+len(data))
+	copy(page, oggCapture)
+	if continued {
+		page[5] = 0x01
+	}
+	binary.LittleEndian.PutUint32(page[18:], seq)
+	page[26] = byte(len(lacing))
+	page = append(page, lacing...)
+	page = append(page, data...)
+
+	var crc uint32
+	for _, b := range page {
+		crc = crc<<8 ^ oggCRC[byte(crc>>24)^b]
+	}
+	binary.LittleEndian.PutUint32(page[22:], crc)
+	return page
+}
+
+func TestOggDemuxer(t *testing.T) {
+	big := bytes.Repeat([]byte{7}, 300) // 跨页的包：255 + 45
+	var stream []byte
+	stream = append(stream, "garbage"...)
+	stream = append(stream, oggPage(0, false, []byte{3, 255}, append([]byte("abc"), big[:255]...))...)
+	stream = append(stream, oggPage(1, true, []byte{45, 2}, append(big[255:], "de"...))...)
+	corrupt := oggPage(2, false, []byte{1}, []byte("x"))
+	corrupt[len(corrupt)-1] = 'y'
+	stream = append(stream, corrupt...)
+	stream = append(stream, oggPage(3, false, []byte{1}, []byte("z"))...)
+
+	var d OggDemuxer
+	var packets [][]byte
+	for i := 0; i < len(stream); i += 7 {
+		packets = append(packets, d.Write(stream[i:min(i+7, len(stream))])...)
+	}
+	want := [][]byte{[]byte("abc"), big, []byte("de"), []byte("z")}
+	if len(packets) != len(want) {
+		t.Fatalf("got %d packets, want %d", len(packets), len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(packets[i], want[i]) {
+			t.Fatalf("packet %d = %q, want %q", i, packets[i], want[i])
+		}
+	}
+	if d.Dropped() != 1 {
+		t.Fatalf("expected 1 dropped page, got %d", d.Dropped())
+	}
+}
+
+// lengthDecoder 每个包解码为 len(packet) 个样本
+type lengthDecoder struct{}
+
+func (lengthDecoder) DecodePacket(packet []byte) ([][2]float64, error) {
+	return make([][2]float64, len(packet)), nil
+}
+
+func TestOggOpusDecoder(t *testing.T) {
+	head := []byte("OpusHead")
+	head = append(head, 1, 1)                          // version, channels
+	head = binary.LittleEndian.AppendUint16(head, 480) // pre-skip 10ms@48k
+	head = binary.LittleEndian.AppendUint32(head, 16000)
+	head = append(head, 0, 0, 0) // gain, mapping family
+
+	var stream []byte
+	stream = append(stream, oggPage(0, false, []byte{byte(len(head))}, head)...)
+	stream = append(stream, oggPage(1, false, []byte{8}, []byte("OpusTags"))...)
+	stream = append(stream, oggPage(2, false, []byte{200, 200}, make([]byte, 400))...)
+
+	var channels, total int
+	newDecoder := OggOpusDecoder(func(sampleRate, ch int) (PacketDecoder, error) {
+		channels = ch
+		return lengthDecoder{}, nil
+	})
+	dec, err := newDecoder(16000, 0, func(samples [][2]float64) { total += len(samples) })
+	if err != nil {
+		t.Fatalf("new decoder: %v", err)
+	}
+	if err := dec.Write(stream); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := dec.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	// 400 个样本去掉 16kHz 下 160 个 pre-skip
+	if channels != 1 || total != 240 {
+		t.Fatalf("unexpected output: channels=%d samples=%d", channels, total)
+	}
+}
@@ -0,0 +1,194 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/gopxl/beep"
+)
+
+const (
+	resampleZeroCrossings = 16   // 每侧的 sinc 过零点数，决定滤波器长度和过渡带宽度
+	resampleKaiserBeta    = 8.6  // Kaiser 窗参数，阻带衰减约 -80dB
+	resampleMaxPhases     = 1024 // 多相滤波器最多的相位数，超出时取最近的相位
+	resampleChunk         = 512  // 每次从源读取的采样点数
+)
+
+// Resampler 基于加窗 sinc 多相滤波器的采样率转换，实现 beep.Streamer
+// 降采样时自动降低截止频率以抗混叠。源暂时没有数据（返回 (0, true)）时同样返回 (0, true)，
+// 因此可以包装仍在接收数据的 tts.Streamer；源结束后输出滤波器中剩余的样本再结束，
+// 源被取消（Err() 为 context.Canceled，如 tts.Streamer.Cancel）时丢弃剩余样本立即结束
+type Resampler struct {
+	src  beep.Streamer
+	from int
+	to   int
+
+	up, down int64        // 转换比例 to/from 化简后的分子分母
+	half     int          // 滤波器每侧的抽头数
+	phases   int          // 多相滤波器的相位数
+	taps     [][]float64  // taps[p] 为相位 p 的 2*half 个系数
+	buf      [][2]float64 // 输入样本，buf[0] 对应绝对下标 base
+	base     int64
+	inputs   int64 // 已读取的真实输入样本数
+	pos      int64 // 下一个输出样本的下标
+	ended    bool
+	stopped  bool // 源被取消，不再输出
+	scratch  [][2]float64
+}
+
+// Resample 把 src 从 from 采样率转换为 to，两者相同时也可以使用（结果等于原样本）
+func Resample(src beep.Streamer, from, to beep.SampleRate) (*Resampler, error) {
+	if from <= 0 || to <= 0 {
+		return nil, fmt.Errorf("audio: invalid sample rate %d -> %d", from, to)
+	}
+	g := gcd(int64(from), int64(to))
+	r := &Resampler{
+		src:     src,
+		from:    int(from),
+		to:      int(to),
+		up:      int64(to) / g,
+		down:    int64(from) / g,
+		scratch: make([][2]float64, resampleChunk),
+	}
+
+	cutoff := math.Min(1, float64(to)/float64(from))
+	r.half = int(math.Ceil(resampleZeroCrossings / cutoff))
+	r.phases = int(min(r.up, resampleMaxPhases))
+	r.taps = make([][]float64, r.phases)
+	for p := range r.taps {
+		frac := float64(p) / float64(r.phases)
+		taps := make([]float64, 2*r.half)
+		sum := 0.0
+		for j := range taps {
+			t := float64(j-r.half+1) - frac
+			taps[j] = cutoff * sinc(cutoff*t) * kaiser(t/float64(r.half), resampleKaiserBeta)
+			sum += taps[j]
+		}
+		// 归一化使直流增益为 1
+		for j := range taps {
+			taps[j] /= sum
+		}
+		r.taps[p] = taps
+	}
+
+	// 开头补 half 个静音，第一个输出样本对齐第一个输入样本
+	r.buf = make([][2]float64, r.half)
+	r.base = -int64(r.half)
+	return r, nil
+}
+
+// SampleRate 返回输出采样率
+func (r *Resampler) SampleRate() beep.SampleRate {
+	return beep.SampleRate(r.to)
+}
+
+func (r *Resampler) Stream(samples [][2]float64) (int, bool) {
+	if r.stopped || errors.Is(r.src.Err(), context.Canceled) {
+		// 打断后不能再播放缓冲中的旧音频和滤波器尾部
+		r.stopped = true
+		r.buf = nil
+		return 0, false
+	}
+	n := 0
+	for n < len(samples) {
+		center := r.pos * r.down / r.up
+		if !r.ended && center+int64(r.half) >= r.base+int64(len(r.buf)) {
+			if !r.fill() {
+				break // 源暂时没有数据
+			}
+			continue
+		}
+		if r.ended && center >= r.inputs {
+			break
+		}
+
+		phase := int(r.pos * r.down % r.up)
+		if int64(r.phases) != r.up {
+			phase = int(int64(phase) * int64(r.phases) / r.up)
+		}
+		taps := r.taps[phase]
+		start := int(center - r.base - int64(r.half) + 1)
+		var l, rr float64
+		for j, c := range taps {
+			s := r.buf[start+j]
+			l += s[0] * c
+			rr += s[1] * c
+		}
+		samples[n] = [2]float64{l, rr}
+		n++
+		r.pos++
+	}
+	r.compact()
+
+	if n == 0 && r.ended {
+		return 0, false
+	}
+	return n, true
+}
+
+// fill 从源读取一块样本，源结束时补齐滤波器尾部的静音；没有读到数据且源未结束时返回 false
+func (r *Resampler) fill() bool {
+	n, ok := r.src.Stream(r.scratch)
+	r.buf = append(r.buf, r.scratch[:n]...)
+	r.inputs += int64(n)
+	if !ok {
+		r.ended = true
+		r.buf = append(r.buf, make([][2]float64, r.half)...)
+		return true
+	}
+	return n > 0
+}
+
+// compact 丢弃不再需要的输入样本
+func (r *Resampler) compact() {
+	center := r.pos * r.down / r.up
+	drop := int(center - r.base - int64(r.half) + 1)
+	if drop <= 0 || drop < len(r.buf)/2 {
+		return
+	}
+	drop = min(drop, len(r.buf))
+	r.buf = append(r.buf[:0], r.buf[drop:]...)
+	r.base += int64(drop)
+}
+
+func (r *Resampler) Err() error {
+	return r.src.Err()
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	x *= math.Pi
+	return math.Sin(x) / x
+}
+
+// kaiser 返回 Kaiser 窗在 x（[-1, 1]）处的值
+func kaiser(x, beta float64) float64 {
+	if x <= -1 || x >= 1 {
+		return 0
+	}
+	return besselI0(beta*math.Sqrt(1-x*x)) / besselI0(beta)
+}
+
+// besselI0 第一类零阶修正贝塞尔函数（级数展开）
+func besselI0(x float64) float64 {
+	sum, term := 1.0, 1.0
+	for k := 1; k < 50; k++ {
+		term *= (x / (2 * float64(k))) * (x / (2 * float64(k)))
+		sum += term
+		if term < sum*1e-12 {
+			break
+		}
+	}
+	return sum
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
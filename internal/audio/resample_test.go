@@ -0,0 +1,68 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gopxl/beep"
+)
+
+// sine 返回 rate 采样率、freq 频率的 n 个正弦样本
+func sine(n int, rate, freq float64) [][2]float64 {
+	samples := make([][2]float64, n)
+	for i := range samples {
+		v := 0.5 * math.Sin(2*math.Pi*freq*float64(i)/rate)
+		samples[i] = [2]float64{v, v}
+	}
+	return samples
+}
+
+// stallingStreamer 每隔一次调用返回 (0, true)，模拟仍在接收数据的 tts.Streamer
+type stallingStreamer struct {
+	sliceStreamer
+	calls int
+}
+
+func (s *stallingStreamer) Stream(samples [][2]float64) (int, bool) {
+	s.calls++
+	if s.calls%2 == 0 {
+		return 0, true
+	}
+	return s.sliceStreamer.Stream(samples[:min(len(samples), 100)])
+}
+
+func TestResample(t *testing.T) {
+	tests := []struct{ from, to int }{
+		{24000, 16000},
+		{16000, 48000},
+		{44100, 16000},
+		{8000, 8000},
+	}
+	for _, tt := range tests {
+		in := sine(tt.from/10, float64(tt.from), 440) // 100ms
+		r, err := Resample(&stallingStreamer{sliceStreamer: sliceStreamer{samples: in}}, beep.SampleRate(tt.from), beep.SampleRate(tt.to))
+		if err != nil {
+			t.Fatalf("%d->%d: %v", tt.from, tt.to, err)
+		}
+
+		var out [][2]float64
+		buf := make([][2]float64, 256)
+		for i := 0; i < 10000; i++ {
+			n, ok := r.Stream(buf)
+			out = append(out, buf[:n]...)
+			if !ok {
+				break
+			}
+		}
+		if want := tt.to / 10; len(out) != want {
+			t.Fatalf("%d->%d: got %d samples, want %d", tt.from, tt.to, len(out), want)
+		}
+		// 跳过两端滤波器的过渡区，与理想正弦比较
+		want := sine(len(out), float64(tt.to), 440)
+		for i := len(out) / 10; i < len(out)*9/10; i++ {
+			if d := math.Abs(out[i][0] - want[i][0]); d > 1e-3 {
+				t.Fatalf("%d->%d: sample %d = %v, want %v", tt.from, tt.to, i, out[i][0], want[i][0])
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	rtpVersion    = 2
+	rtpHeaderSize = 12
+)
+
+// ErrInvalidRTPPacket 数据不是合法的 RTP 包
+var ErrInvalidRTPPacket = errors.New("audio: invalid rtp packet")
+
+// RTPPacket RTP 包（RFC 3550），只支持读取不支持写出扩展头和 CSRC
+type RTPPacket struct {
+	Marker         bool
+	PayloadType    uint8
+	SequenceNumber uint16
+	Timestamp      uint32
+	SSRC           uint32
+	Payload        []byte
+}
+
+// Marshal 编码为网络字节序的 RTP 包
+func (p *RTPPacket) Marshal() []byte {
+	b := make([]byte, rtpHeaderSize+len(p.Payload))
+	b[0] = rtpVersion << 6
+	b[1] = p.PayloadType & 0x7f
+	if p.Marker {
+		b[1] |= 0x80
+	}
+	binary.BigEndian.PutUint16(b[2:], p.SequenceNumber)
+	binary.BigEndian.PutUint32(b[4:], p.Timestamp)
+	binary.BigEndian.PutUint32(b[8:], p.SSRC)
+	copy(b[rtpHeaderSize:], p.Payload)
+	return b
+}
+
+// Unmarshal 解析 RTP 包，跳过 CSRC 列表和扩展头并去掉填充；Payload 引用 b
+func (p *RTPPacket) Unmarshal(b []byte) error {
+	if len(b) < rtpHeaderSize {
+		return fmt.Errorf("%w: %d bytes", ErrInvalidRTPPacket, len(b))
+	}
+	if v := b[0] >> 6; v != rtpVersion {
+		return fmt.Errorf("%w: version %d", ErrInvalidRTPPacket, v)
+	}
+	padding := b[0]&0x20 != 0
+	extension := b[0]&0x10 != 0
+	csrc := int(b[0] & 0x0f)
+
+	p.Marker = b[1]&0x80 != 0
+	p.PayloadType = b[1] & 0x7f
+	p.SequenceNumber = binary.BigEndian.Uint16(b[2:])
+	p.Timestamp = binary.BigEndian.Uint32(b[4:])
+	p.SSRC = binary.BigEndian.Uint32(b[8:])
+
+	offset := rtpHeaderSize + csrc*4
+	if extension {
+		if len(b) < offset+4 {
+			return fmt.Errorf("%w: truncated extension", ErrInvalidRTPPacket)
+		}
+		offset += 4 + int(binary.BigEndian.Uint16(b[offset+2:]))*4
+	}
+	end := len(b)
+	if padding && end > 0 {
+		end -= int(b[end-1])
+	}
+	if offset > end {
+		return fmt.Errorf("%w: header exceeds packet", ErrInvalidRTPPacket)
+	}
+	p.Payload = b[offset:end]
+	return nil
+}
+
+// swapPCMOrder 在小端 PCM 和 RTP 要求的网络字节序（RFC 3551 L16/L24）之间转换，原地修改
+func swapPCMOrder(b []byte, codec CodecOption) {
+	if codec.Codec != "pcm" {
+		return
+	}
+	width := codec.BitDepth / 8
+	if width < 2 {
+		return
+	}
+	for i := 0; i+width <= len(b); i += width {
+		for l, r := i, i+width-1; l < r; l, r = l+1, r-1 {
+			b[l], b[r] = b[r], b[l]
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+)
+
+// RTPConfig RTP 收发配置
+type RTPConfig struct {
+	SSRC     uint32 // 发送端：0 时随机生成；接收端：非 0 时只接收该 SSRC 的包
+	Realtime bool   // 发送端 Send 是否按帧时长的实时节奏发送（对端通常没有足够的缓冲），只对发送端有效
+}
+
+// DefaultRTPConfig 返回默认配置
+func DefaultRTPConfig() RTPConfig {
+	return RTPConfig{Realtime: true}
+}
+
+func resolveRTPConfig(cfg []RTPConfig) RTPConfig {
+	if len(cfg) > 0 {
+		return cfg[0]
+	}
+	return DefaultRTPConfig()
+}
+
+// RTPStats 收发统计
+type RTPStats struct {
+	Packets uint64 // 发送或接收的包数
+	Bytes   uint64 // 负载字节数
+	Lost    uint64 // 接收端根据序号推算的丢包数
+	Dropped uint64 // 接收端丢弃的包数（格式错误、负载类型或 SSRC 不匹配、重复或乱序）
+}
+
+// RTPSender 把 Frame 封装为 RTP 包通过 UDP 发送
+// 时间戳按采样点数递增，Frame.IsFirst 的包带 marker 位；16 位以上 PCM 按 RFC 3551 转为网络字节序
+type RTPSender struct {
+	conn  net.Conn
+	codec CodecOption
+	cfg   RTPConfig
+
+	mu    sync.Mutex
+	ssrc  uint32
+	seq   uint16
+	ts    uint32
+	stats RTPStats
+}
+
+// DialRTP 创建发送到 addr（host:port）的 RTPSender
+func DialRTP(addr string, codec CodecOption, cfg ...RTPConfig) (*RTPSender, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s, err := NewRTPSender(conn, codec, cfg...)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewRTPSender 使用已连接的 conn 创建 RTPSender，codec 描述待发送帧的格式
+func NewRTPSender(conn net.Conn, codec CodecOption, cfg ...RTPConfig) (*RTPSender, error) {
+	c, err := NewCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	codec = c.Option()
+	rc := resolveRTPConfig(cfg)
+	ssrc := rc.SSRC
+	if ssrc == 0 {
+		ssrc = rand.Uint32()
+	}
+	// 序号和时间戳的初始值随机（RFC 3550 5.1）
+	return &RTPSender{
+		conn:  conn,
+		codec: codec,
+		cfg:   rc,
+		ssrc:  ssrc,
+		seq:   uint16(rand.Uint32()),
+		ts:    rand.Uint32(),
+	}, nil
+}
+
+// SSRC 返回发送使用的同步源标识
+func (s *RTPSender) SSRC() uint32 {
+	return s.ssrc
+}
+
+// LocalAddr 返回本地地址
+func (s *RTPSender) LocalAddr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+// Stats 返回发送统计
+func (s *RTPSender) Stats() RTPStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// WriteFrame 立即把一帧作为一个 RTP 包发送，Payload 为空的帧（如结束帧）不发送
+func (s *RTPSender) WriteFrame(frame Frame) error {
+	if len(frame.Payload) == 0 {
+		return nil
+	}
+	payload := make([]byte, len(frame.Payload))
+	copy(payload, frame.Payload)
+	swapPCMOrder(payload, s.codec)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pkt := RTPPacket{
+		Marker:         frame.IsFirst,
+		PayloadType:    s.codec.PayloadType,
+		SequenceNumber: s.seq,
+		Timestamp:      s.ts,
+		SSRC:           s.ssrc,
+		Payload:        payload,
+	}
+	if _, err := s.conn.Write(pkt.Marshal()); err != nil {
+		return err
+	}
+	s.seq++
+	s.ts += uint32(len(payload) / s.codec.BytesPerSample())
+	s.stats.Packets++
+	s.stats.Bytes += uint64(len(payload))
+	return nil
+}
+
+// Send 读取 src 直到结束，按 codec.FrameDuration 重新切帧（尾帧补静音）后发送
+// src 正常结束时返回 nil；ctx 取消时关闭 src 并返回 ctx.Err()
+func (s *RTPSender) Send(ctx context.Context, src Stream) error {
+	slicer, err := NewSlicer(s.codec, SlicerConfig{Pad: true})
+	if err != nil {
+		return err
+	}
+
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		// 关闭 src 使阻塞中的 Read 返回
+		select {
+		case <-ctx.Done():
+			src.Close()
+		case <-finished:
+		}
+	}()
+
+	start := time.Now()
+	var elapsed time.Duration
+	send := func(frames ...Frame) error {
+		for _, frame := range frames {
+			if s.cfg.Realtime {
+				if err := sleepUntil(ctx, start.Add(elapsed)); err != nil {
+					return err
+				}
+			}
+			if err := s.WriteFrame(frame); err != nil {
+				return err
+			}
+			elapsed += s.codec.Duration(len(frame.Payload))
+		}
+		return nil
+	}
+
+	for {
+		frame, err := src.Read()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, io.EOF) {
+			return send(slicer.Flush())
+		}
+		if err != nil {
+			return err
+		}
+		if err := send(slicer.Write(frame.Payload)...); err != nil {
+			return err
+		}
+		if frame.IsLast {
+			return send(slicer.Flush())
+		}
+	}
+}
+
+// Close 关闭底层连接
+func (s *RTPSender) Close() error {
+	return s.conn.Close()
+}
+
+func sleepUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RTPReceiver 从 UDP 接收 RTP 包，解出的帧写入 Stream()
+// 只接收 codec.PayloadType 的包；丢弃重复和迟到的包，不做抖动缓冲和丢包补偿。
+// 带 marker 位的包或 SSRC 变化后的第一个包带 IsFirst；连接关闭后 Stream 结束
+type RTPReceiver struct {
+	conn  net.PacketConn
+	codec CodecOption
+	cfg   RTPConfig
+	pipe  *Pipe
+
+	mu      sync.Mutex
+	stats   RTPStats
+	started bool
+	ssrc    uint32
+	lastSeq uint16
+}
+
+// ListenRTP 在 addr（如 "127.0.0.1:0"）上监听 RTP
+func ListenRTP(addr string, codec CodecOption, cfg ...RTPConfig) (*RTPReceiver, error) {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewRTPReceiver(conn, codec, cfg...)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// NewRTPReceiver 在 conn 上接收 RTP，codec 描述负载格式
+func NewRTPReceiver(conn net.PacketConn, codec CodecOption, cfg ...RTPConfig) (*RTPReceiver, error) {
+	c, err := NewCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	codec = c.Option()
+	r := &RTPReceiver{
+		conn:  conn,
+		codec: codec,
+		cfg:   resolveRTPConfig(cfg),
+		pipe:  NewPipe(codec, 0),
+	}
+	go r.run()
+	return r, nil
+}
+
+// Stream 返回接收到的帧，消费过慢时接收会阻塞，超出系统 UDP 缓冲的包会被丢弃
+func (r *RTPReceiver) Stream() Stream {
+	return r.pipe
+}
+
+// LocalAddr 返回监听地址
+func (r *RTPReceiver) LocalAddr() net.Addr {
+	return r.conn.LocalAddr()
+}
+
+// Stats 返回接收统计
+func (r *RTPReceiver) Stats() RTPStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// Close 关闭连接，Stream 中已缓冲的帧仍然可以读出
+func (r *RTPReceiver) Close() error {
+	return r.conn.Close()
+}
+
+func (r *RTPReceiver) run() {
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := r.conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				r.pipe.Close()
+			} else {
+				r.pipe.CloseWithError(err)
+			}
+			return
+		}
+		frame, ok := r.handle(buf[:n])
+		if !ok {
+			continue
+		}
+		if err := r.pipe.Write(frame); err != nil {
+			// 读取方关闭了 Stream，不再需要接收
+			r.conn.Close()
+			return
+		}
+	}
+}
+
+// handle 解析一个包并更新统计，返回要写入 Stream 的帧
+func (r *RTPReceiver) handle(b []byte) (Frame, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pkt RTPPacket
+	if err := pkt.Unmarshal(b); err != nil ||
+		pkt.PayloadType != r.codec.PayloadType ||
+		(r.cfg.SSRC != 0 && pkt.SSRC != r.cfg.SSRC) {
+		r.stats.Dropped++
+		return Frame{}, false
+	}
+
+	first := pkt.Marker
+	if !r.started || pkt.SSRC != r.ssrc {
+		// 新的发送源
+		r.started = true
+		r.ssrc = pkt.SSRC
+		first = true
+	} else {
+		diff := int16(pkt.SequenceNumber - r.lastSeq)
+		if diff <= 0 {
+			r.stats.Dropped++
+			return Frame{}, false
+		}
+		r.stats.Lost += uint64(diff - 1)
+	}
+	r.lastSeq = pkt.SequenceNumber
+	r.stats.Packets++
+	r.stats.Bytes += uint64(len(pkt.Payload))
+
+	payload := make([]byte, len(pkt.Payload))
+	copy(payload, pkt.Payload)
+	swapPCMOrder(payload, r.codec)
+	return Frame{Payload: payload, IsFirst: first}, true
+}
@@ -0,0 +1,92 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRTPPacketUnmarshal(t *testing.T) {
+	pkt := RTPPacket{Marker: true, PayloadType: 96, SequenceNumber: 65535, Timestamp: 1 << 31, SSRC: 42, Payload: []byte{1, 2, 3}}
+	b := pkt.Marshal()
+
+	// 追加一个 CSRC、一个 4 字节扩展头和 2 字节填充
+	raw := append([]byte{}, b[:rtpHeaderSize]...)
+	raw[0] |= 0x20 | 0x10 | 1
+	raw = append(raw, 0, 0, 0, 7)
+	raw = append(raw, 0xbe, 0xde, 0, 1, 9, 9, 9, 9)
+	raw = append(raw, pkt.Payload...)
+	raw = append(raw, 0, 2)
+
+	for _, data := range [][]byte{b, raw} {
+		var got RTPPacket
+		if err := got.Unmarshal(data); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if got.Marker != pkt.Marker || got.PayloadType != pkt.PayloadType || got.SequenceNumber != pkt.SequenceNumber ||
+			got.Timestamp != pkt.Timestamp || got.SSRC != pkt.SSRC || !bytes.Equal(got.Payload, pkt.Payload) {
+			t.Fatalf("unexpected packet: %+v", got)
+		}
+	}
+
+	var got RTPPacket
+	if err := got.Unmarshal(b[:8]); err == nil {
+		t.Fatal("expected error for short packet")
+	}
+}
+
+func TestRTPLoopback(t *testing.T) {
+	codec := CodecOption{SampleRate: 8000, Channels: 1, BitDepth: 16, FrameDuration: "10ms", PayloadType: 96}
+	receiver, err := ListenRTP("127.0.0.1:0", codec)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer receiver.Close()
+	sender, err := DialRTP(receiver.LocalAddr().String(), codec, RTPConfig{SSRC: 7, Realtime: true})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer sender.Close()
+
+	// 250 个采样点，按 80 个采样点一帧发送 4 帧，最后一帧补静音
+	input := make([]byte, 500)
+	for i := range input {
+		input[i] = byte(i)
+	}
+	src := NewPipe(codec, 10)
+	src.Write(Frame{Payload: input[:300], IsFirst: true})
+	src.Write(Frame{Payload: input[300:], IsLast: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := sender.Send(ctx, src); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	// 实时发送 4 帧至少需要 30ms
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Fatalf("send not paced, elapsed=%v", elapsed)
+	}
+
+	var got []byte
+	for i := 0; i < 4; i++ {
+		frame, err := receiver.Stream().Read()
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if frame.IsFirst != (i == 0) {
+			t.Fatalf("frame %d IsFirst=%v", i, frame.IsFirst)
+		}
+		got = append(got, frame.Payload...)
+	}
+	if len(got) != 640 || !bytes.Equal(got[:500], input) || !bytes.Equal(got[500:], make([]byte, 140)) {
+		t.Fatalf("unexpected payload (%d bytes)", len(got))
+	}
+	if s := receiver.Stats(); s.Packets != 4 || s.Lost != 0 || s.Dropped != 0 {
+		t.Fatalf("unexpected receiver stats: %+v", s)
+	}
+	if s := sender.Stats(); s.Packets != 4 || s.Bytes != 640 {
+		t.Fatalf("unexpected sender stats: %+v", s)
+	}
+}
@@ -0,0 +1,150 @@
+package audio
+
+import (
+	"sync"
+)
+
+// SlicerConfig Slicer 配置
+type SlicerConfig struct {
+	Pad bool // Flush 时用静音把不足一帧的尾部补齐到完整帧（RTP、电话等要求固定帧长的传输需要）
+}
+
+// DefaultSlicerConfig 返回默认配置（不补齐尾部）
+func DefaultSlicerConfig() SlicerConfig {
+	return SlicerConfig{}
+}
+
+// Slicer 把任意长度的音频数据块重新切成 codec.FrameDuration 时长的 Frame
+// 第一帧带 IsFirst，Flush 返回的最后一帧带 IsLast；不是并发安全的
+type Slicer struct {
+	codec *Codec
+	cfg   SlicerConfig
+	size  int // 每帧字节数
+
+	buf     []byte
+	started bool
+}
+
+// NewSlicer 创建 Slicer，cfg 可选
+func NewSlicer(codec CodecOption, cfg ...SlicerConfig) (*Slicer, error) {
+	c, err := NewCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	codec = c.Option()
+	samples, err := codec.FrameSamples()
+	if err != nil {
+		return nil, err
+	}
+	sc := DefaultSlicerConfig()
+	if len(cfg) > 0 {
+		sc = cfg[0]
+	}
+	return &Slicer{
+		codec: c,
+		cfg:   sc,
+		size:  samples * codec.BytesPerSample(),
+	}, nil
+}
+
+// FrameSize 返回每帧的字节数
+func (s *Slicer) FrameSize() int {
+	return s.size
+}
+
+// Buffered 返回尚不足一帧、等待后续数据的字节数
+func (s *Slicer) Buffered() int {
+	return len(s.buf)
+}
+
+// Write 追加 p 并返回凑满的完整帧，不足一帧的部分留到下次 Write 或 Flush
+// 返回的 Payload 不引用 p，调用方可以复用 p
+func (s *Slicer) Write(p []byte) []Frame {
+	s.buf = append(s.buf, p...)
+	var frames []Frame
+	for len(s.buf) >= s.size {
+		payload := make([]byte, s.size)
+		copy(payload, s.buf)
+		s.buf = s.buf[s.size:]
+		frames = append(frames, s.frame(payload))
+	}
+	if len(s.buf) == 0 {
+		s.buf = nil
+	}
+	return frames
+}
+
+// Flush 返回剩余数据组成的最后一帧（IsLast），并重置 Slicer 以便开始下一段音频
+// 尾部不完整的采样点会被丢弃；开启 Pad 时补齐到完整帧。没有剩余数据时返回 Payload 为空的结束帧
+func (s *Slicer) Flush() Frame {
+	width := s.codec.Option().BytesPerSample()
+	tail := s.buf[:len(s.buf)/width*width]
+	n := len(tail)
+	if s.cfg.Pad && n > 0 {
+		n = s.size
+	}
+	payload := make([]byte, n)
+	copy(payload, tail)
+	s.codec.Silence(payload[len(tail):])
+
+	frame := s.frame(payload)
+	frame.IsLast = true
+	s.Reset()
+	return frame
+}
+
+// Reset 丢弃缓冲的数据，下一帧重新带 IsFirst
+func (s *Slicer) Reset() {
+	s.buf = nil
+	s.started = false
+}
+
+func (s *Slicer) frame(payload []byte) Frame {
+	frame := Frame{Payload: payload, IsFirst: !s.started}
+	s.started = true
+	return frame
+}
+
+// SliceWriter 把写入的任意长度音频数据切成固定时长的帧写入 Stream
+// 适合把 TTS websocket 收到的音频块直接转给 RTP 等传输；Close 时写入最后一帧（IsLast）
+type SliceWriter struct {
+	mu     sync.Mutex
+	dst    Stream
+	slicer *Slicer
+	closed bool
+}
+
+// NewSliceWriter 创建写入 dst 的 SliceWriter，codec 描述写入数据的格式
+func NewSliceWriter(dst Stream, codec CodecOption, cfg ...SlicerConfig) (*SliceWriter, error) {
+	slicer, err := NewSlicer(codec, cfg...)
+	if err != nil {
+		return nil, err
+	}
+	return &SliceWriter{dst: dst, slicer: slicer}, nil
+}
+
+// Write 实现 io.Writer，dst 缓冲满时阻塞
+func (w *SliceWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrStreamClosed
+	}
+	for _, frame := range w.slicer.Write(p) {
+		if err := w.dst.Write(frame); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close 写入剩余数据组成的最后一帧，dst 随之结束
+func (w *SliceWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.dst.Write(w.slicer.Flush())
+}
@@ -0,0 +1,62 @@
+package audio
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSlicer(t *testing.T) {
+	// 8kHz 16 位单声道，10ms 一帧 = 160 字节
+	codec := CodecOption{SampleRate: 8000, Channels: 1, BitDepth: 16, FrameDuration: "10ms"}
+	tests := []struct {
+		name   string
+		chunks []int
+		pad    bool
+		want   []int // 各帧长度，最后一个为 Flush 的结果
+	}{
+		{name: "exact", chunks: []int{160, 160}, want: []int{160, 160, 0}},
+		{name: "small chunks", chunks: []int{50, 50, 50, 50, 50}, want: []int{160, 90}},
+		{name: "large chunk", chunks: []int{400}, want: []int{160, 160, 80}},
+		{name: "pad tail", chunks: []int{200}, pad: true, want: []int{160, 160}},
+		{name: "drop odd byte", chunks: []int{165}, want: []int{160, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSlicer(codec, SlicerConfig{Pad: tt.pad})
+			if err != nil {
+				t.Fatalf("new slicer: %v", err)
+			}
+			var input, output []byte
+			var frames []Frame
+			for _, n := range tt.chunks {
+				chunk := make([]byte, n)
+				for i := range chunk {
+					chunk[i] = byte(len(input) + i + 1)
+				}
+				input = append(input, chunk...)
+				frames = append(frames, s.Write(chunk)...)
+			}
+			frames = append(frames, s.Flush())
+
+			if len(frames) != len(tt.want) {
+				t.Fatalf("got %d frames, want %d", len(frames), len(tt.want))
+			}
+			for i, f := range frames {
+				if len(f.Payload) != tt.want[i] {
+					t.Fatalf("frame %d size = %d, want %d", i, len(f.Payload), tt.want[i])
+				}
+				if f.IsFirst != (i == 0) || f.IsLast != (i == len(frames)-1) {
+					t.Fatalf("frame %d flags first=%v last=%v", i, f.IsFirst, f.IsLast)
+				}
+				output = append(output, f.Payload...)
+			}
+			n := min(len(input), len(output))
+			if !bytes.Equal(output[:n], input[:n]) {
+				t.Fatal("payload mismatch")
+			}
+			if tt.pad && !bytes.Equal(output[len(input):], make([]byte, len(output)-len(input))) {
+				t.Fatal("tail not padded with silence")
+			}
+		})
+	}
+}
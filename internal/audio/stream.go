@@ -1,8 +1,131 @@
 package audio
 
-// type Stream interface {
-// 	Write(frame Frame) error
-// 	Read() (*Frame, error)
-// 	Close() error
-// 	Done() <-chan struct{}
-// }
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrStreamClosed 向已关闭的 Stream 写入
+var ErrStreamClosed = errors.New("audio: stream closed")
+
+// Stream 音频帧管道，TTS 输出、麦克风输入和网络传输之间统一用它传递 Frame
+type Stream interface {
+	Write(frame Frame) error // 写入一帧，缓冲满时阻塞（背压）；写入 IsLast 的帧后不能再写入
+	Read() (*Frame, error)   // 读取一帧，没有数据时阻塞；关闭且缓冲读完后返回 io.EOF 或 CloseWithError 的错误
+	Close() error            // 关闭写入端，已缓冲的帧仍然可以读出
+	Done() <-chan struct{}   // Close 后关闭
+}
+
+// DefaultPipeCapacity Pipe 默认缓冲的帧数（20ms 一帧时约 1 秒）
+const DefaultPipeCapacity = 50
+
+// Pipe 基于有界 channel 的 Stream 实现，并发安全，支持一个或多个读写方
+type Pipe struct {
+	codec  CodecOption
+	frames chan Frame
+	done   chan struct{}
+
+	mu     sync.Mutex // 保证 IsLast 帧之后不再写入
+	closed bool
+	err    error // Close 的原因，nil 表示正常结束
+}
+
+var _ Stream = (*Pipe)(nil)
+
+// NewPipe 创建 Pipe，codec 描述帧的格式，capacity <= 0 时使用 DefaultPipeCapacity
+func NewPipe(codec CodecOption, capacity int) *Pipe {
+	if capacity <= 0 {
+		capacity = DefaultPipeCapacity
+	}
+	return &Pipe{
+		codec:  codec.WithDefaults(),
+		frames: make(chan Frame, capacity),
+		done:   make(chan struct{}),
+	}
+}
+
+// Codec 返回帧的格式
+func (p *Pipe) Codec() CodecOption {
+	return p.codec
+}
+
+// Len 返回已缓冲的帧数
+func (p *Pipe) Len() int {
+	return len(p.frames)
+}
+
+func (p *Pipe) Write(frame Frame) error {
+	return p.WriteContext(context.Background(), frame)
+}
+
+// WriteContext 写入一帧，缓冲满时阻塞直到有空间、Pipe 关闭或 ctx 取消
+func (p *Pipe) WriteContext(ctx context.Context, frame Frame) error {
+	select {
+	case <-p.done:
+		return ErrStreamClosed
+	default:
+	}
+
+	select {
+	case p.frames <- frame:
+	case <-p.done:
+		return ErrStreamClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if frame.IsLast {
+		p.Close()
+	}
+	return nil
+}
+
+func (p *Pipe) Read() (*Frame, error) {
+	return p.ReadContext(context.Background())
+}
+
+// ReadContext 读取一帧，没有数据时阻塞直到有数据、Pipe 关闭或 ctx 取消
+func (p *Pipe) ReadContext(ctx context.Context) (*Frame, error) {
+	select {
+	case frame := <-p.frames:
+		return &frame, nil
+	case <-p.done:
+		// 关闭前写入的帧仍然返回
+		select {
+		case frame := <-p.frames:
+			return &frame, nil
+		default:
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.err != nil {
+			return nil, p.err
+		}
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *Pipe) Close() error {
+	return p.CloseWithError(nil)
+}
+
+// CloseWithError 关闭 Pipe，缓冲读完后 Read 返回 err（err 为 nil 时返回 io.EOF），重复关闭时保留第一次的原因
+func (p *Pipe) CloseWithError(err error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	p.err = err
+	close(p.done)
+	return nil
+}
+
+func (p *Pipe) Done() <-chan struct{} {
+	return p.done
+}
@@ -0,0 +1,113 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gopxl/beep"
+)
+
+func TestPipe(t *testing.T) {
+	p := NewPipe(CodecOption{}, 2)
+	if err := p.Write(Frame{Payload: []byte{1}, IsFirst: true}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := p.Write(Frame{Payload: []byte{2}}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// 缓冲已满，写入阻塞到读出一帧
+	written := make(chan error, 1)
+	go func() { written <- p.Write(Frame{Payload: []byte{3}, IsLast: true}) }()
+	select {
+	case err := <-written:
+		t.Fatalf("write should block when full, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	var got []byte
+	for {
+		frame, err := p.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		got = append(got, frame.Payload...)
+	}
+	if err := <-written; err != nil {
+		t.Fatalf("blocked write: %v", err)
+	}
+	if !bytes.Equal(got, []byte{1, 2, 3}) {
+		t.Fatalf("unexpected frames: %v", got)
+	}
+	// IsLast 之后不能再写入
+	if err := p.Write(Frame{}); !errors.Is(err, ErrStreamClosed) {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+
+	failed := NewPipe(CodecOption{}, 1)
+	boom := errors.New("boom")
+	failed.CloseWithError(boom)
+	if _, err := failed.Read(); !errors.Is(err, boom) {
+		t.Fatalf("expected close error, got %v", err)
+	}
+}
+
+func TestBeepRoundTrip(t *testing.T) {
+	// 50ms 的 16kHz 单声道锯齿波
+	samples := make([][2]float64, 800)
+	for i := range samples {
+		v := float64(i%100)/100 - 0.5
+		samples[i] = [2]float64{v, v}
+	}
+	src := beep.Take(len(samples), &sliceStreamer{samples: samples})
+
+	codec := CodecOption{SampleRate: 16000, Channels: 1, BitDepth: 16, FrameDuration: "20ms"}
+	stream, err := FromBeep(src, codec)
+	if err != nil {
+		t.Fatalf("from beep: %v", err)
+	}
+	out, err := ToBeep(stream, codec)
+	if err != nil {
+		t.Fatalf("to beep: %v", err)
+	}
+
+	got := make([][2]float64, 0, len(samples))
+	buf := make([][2]float64, 300)
+	for {
+		n, ok := out.Stream(buf)
+		got = append(got, buf[:n]...)
+		if !ok {
+			break
+		}
+	}
+	if len(got) != len(samples) {
+		t.Fatalf("unexpected sample count: %d", len(got))
+	}
+	for i := range samples {
+		if d := got[i][0] - samples[i][0]; d > 1e-4 || d < -1e-4 {
+			t.Fatalf("sample %d = %v, want %v", i, got[i][0], samples[i][0])
+		}
+	}
+	if out.Err() != nil {
+		t.Fatalf("unexpected error: %v", out.Err())
+	}
+}
+
+type sliceStreamer struct {
+	samples [][2]float64
+	pos     int
+}
+
+func (s *sliceStreamer) Stream(samples [][2]float64) (int, bool) {
+	n := copy(samples, s.samples[s.pos:])
+	s.pos += n
+	return n, n > 0
+}
+
+func (s *sliceStreamer) Err() error { return nil }
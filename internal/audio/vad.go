@@ -0,0 +1,241 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// VADEventType 语音活动事件类型
+type VADEventType int
+
+const (
+	VADSpeechStart VADEventType = iota + 1 // 开始说话
+	VADSpeechEnd                           // 停止说话
+)
+
+func (t VADEventType) String() string {
+	switch t {
+	case VADSpeechStart:
+		return "speech_start"
+	case VADSpeechEnd:
+		return "speech_end"
+	default:
+		return fmt.Sprintf("VADEventType(%d)", int(t))
+	}
+}
+
+// VADEvent 语音活动事件
+type VADEvent struct {
+	Type   VADEventType
+	Time   time.Duration // 事件在音频流中的位置：开始说话为第一个有声窗口的起点，停止说话为静音开始的位置
+	Energy float64       // 触发事件的窗口能量（dBFS）
+}
+
+// VADConfig 语音活动检测配置，能量单位均为 dB
+type VADConfig struct {
+	StartThreshold float64       // 能量高于噪声底多少 dB 算作有声，默认 12
+	EndThreshold   float64       // 说话过程中能量低于噪声底 + 该值算作静音，默认 6，小于 StartThreshold 形成滞回
+	MinEnergy      float64       // 绝对能量下限（dBFS），低于它始终算作静音，默认 -50
+	MinSpeech      time.Duration // 连续有声多久才判定开始说话，用于过滤咳嗽、敲击等短噪声，默认 100ms
+	Hangover       time.Duration // 连续静音多久才判定停止说话，默认 500ms
+	NoiseAdapt     float64       // 静音时噪声底向当前能量靠近的比例（每个窗口），默认 0.05
+}
+
+// DefaultVADConfig 返回默认配置
+func DefaultVADConfig() VADConfig {
+	return VADConfig{
+		StartThreshold: 12,
+		EndThreshold:   6,
+		MinEnergy:      -50,
+		MinSpeech:      100 * time.Millisecond,
+		Hangover:       500 * time.Millisecond,
+		NoiseAdapt:     0.05,
+	}
+}
+
+// minEnergy 全零窗口的能量
+const minEnergy = -100.0
+
+// VAD 基于短时能量和自适应噪声底的语音活动检测
+// 输入按 CodecOption.FrameDuration 切成窗口逐个判断，可用于在说话时才把音频送给 ASR，
+// 或者在播放期间检测到用户开口时调用 Speaker.Stop 打断播放。不是并发安全的
+type VAD struct {
+	cfg    VADConfig
+	codec  CodecOption
+	dec    *Codec
+	window int // 每个窗口的字节数
+
+	buf        []byte        // 不足一个窗口的剩余数据
+	samples    [][2]float64  // 解码窗口用的缓冲
+	pos        time.Duration // 已处理的音频时长
+	noiseFloor float64
+	speaking   bool
+	runStart   time.Duration // 当前连续有声/静音段的起点
+	runLength  time.Duration // 当前连续有声（未说话时）或静音（说话时）的时长
+	runEnergy  float64       // 当前连续段第一个窗口的能量
+}
+
+// NewVAD 创建 VAD，codec 描述输入的音频格式，支持 RegisterCodec 注册的所有编码（单/双声道）
+func NewVAD(codec CodecOption, cfg ...VADConfig) (*VAD, error) {
+	dec, err := NewCodec(codec)
+	if err != nil {
+		return nil, fmt.Errorf("audio: vad: %w", err)
+	}
+	codec = dec.Option()
+	frameDuration, err := codec.FrameDurationValue()
+	if err != nil {
+		return nil, err
+	}
+
+	c := DefaultVADConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	d := DefaultVADConfig()
+	if c.StartThreshold <= 0 {
+		c.StartThreshold = d.StartThreshold
+	}
+	if c.EndThreshold <= 0 || c.EndThreshold > c.StartThreshold {
+		c.EndThreshold = min(d.EndThreshold, c.StartThreshold)
+	}
+	if c.MinEnergy == 0 {
+		c.MinEnergy = d.MinEnergy
+	}
+	if c.MinSpeech <= 0 {
+		c.MinSpeech = d.MinSpeech
+	}
+	if c.Hangover <= 0 {
+		c.Hangover = d.Hangover
+	}
+	if c.NoiseAdapt <= 0 || c.NoiseAdapt > 1 {
+		c.NoiseAdapt = d.NoiseAdapt
+	}
+
+	samples := int(int64(codec.SampleRate) * int64(frameDuration) / int64(time.Second))
+	if samples <= 0 {
+		return nil, fmt.Errorf("audio: vad: frame duration %s too short", frameDuration)
+	}
+
+	v := &VAD{
+		cfg:    c,
+		codec:  codec,
+		dec:    dec,
+		window: samples * codec.BytesPerSample(),
+	}
+	v.Reset()
+	return v, nil
+}
+
+// Process 处理一帧音频，返回期间发生的事件（通常为空）
+// frame.IsLast 时如果仍在说话会补发 VADSpeechEnd
+func (v *VAD) Process(frame Frame) []VADEvent {
+	var events []VADEvent
+	v.buf = append(v.buf, frame.Payload...)
+	n := 0
+	for ; n+v.window <= len(v.buf); n += v.window {
+		if e, ok := v.step(v.buf[n : n+v.window]); ok {
+			events = append(events, e)
+		}
+	}
+	v.buf = append(v.buf[:0], v.buf[n:]...)
+
+	if frame.IsLast {
+		if v.speaking {
+			events = append(events, VADEvent{Type: VADSpeechEnd, Time: v.pos, Energy: v.runEnergy})
+		}
+		v.Reset()
+	}
+	return events
+}
+
+// Speaking 当前是否处于说话状态
+func (v *VAD) Speaking() bool {
+	return v.speaking
+}
+
+// NoiseFloor 当前估计的噪声底（dBFS）
+func (v *VAD) NoiseFloor() float64 {
+	return v.noiseFloor
+}
+
+// Reset 清空状态，开始处理新的音频流
+func (v *VAD) Reset() {
+	v.buf = v.buf[:0]
+	v.pos = 0
+	v.noiseFloor = v.cfg.MinEnergy - v.cfg.StartThreshold
+	v.speaking = false
+	v.runStart = 0
+	v.runLength = 0
+}
+
+// step 处理一个窗口
+func (v *VAD) step(window []byte) (VADEvent, bool) {
+	energy := v.energy(window)
+	start := v.pos
+	length := v.codec.Duration(len(window))
+	v.pos += length
+
+	if !v.speaking {
+		if energy >= math.Max(v.noiseFloor+v.cfg.StartThreshold, v.cfg.MinEnergy) {
+			if v.runLength == 0 {
+				v.runStart, v.runEnergy = start, energy
+			}
+			v.runLength += length
+			if v.runLength >= v.cfg.MinSpeech {
+				v.speaking = true
+				v.runLength = 0
+				return VADEvent{Type: VADSpeechStart, Time: v.runStart, Energy: v.runEnergy}, true
+			}
+			return VADEvent{}, false
+		}
+		v.runLength = 0
+		v.adaptNoise(energy)
+		return VADEvent{}, false
+	}
+
+	if energy < math.Max(v.noiseFloor+v.cfg.EndThreshold, v.cfg.MinEnergy) {
+		if v.runLength == 0 {
+			v.runStart, v.runEnergy = start, energy
+		}
+		v.runLength += length
+		if v.runLength >= v.cfg.Hangover {
+			v.speaking = false
+			v.runLength = 0
+			return VADEvent{Type: VADSpeechEnd, Time: v.runStart, Energy: v.runEnergy}, true
+		}
+		return VADEvent{}, false
+	}
+	v.runLength = 0
+	return VADEvent{}, false
+}
+
+// adaptNoise 静音时更新噪声底：能量下降时快速跟随，上升时缓慢跟随，避免把说话声学习成噪声
+func (v *VAD) adaptNoise(energy float64) {
+	rate := v.cfg.NoiseAdapt
+	if energy < v.noiseFloor {
+		rate = 0.5
+	}
+	v.noiseFloor += (energy - v.noiseFloor) * rate
+}
+
+// energy 计算窗口的 RMS 能量（dBFS），多声道取所有声道的平均
+func (v *VAD) energy(window []byte) float64 {
+	if n := len(window) / v.codec.BytesPerSample(); len(v.samples) < n {
+		v.samples = make([][2]float64, n)
+	}
+	count := v.dec.Decode(v.samples, window)
+	if count == 0 {
+		return minEnergy
+	}
+
+	var sum float64
+	for _, s := range v.samples[:count] {
+		sum += (s[0]*s[0] + s[1]*s[1]) / 2
+	}
+	rms := math.Sqrt(sum / float64(count))
+	if rms <= 0 {
+		return minEnergy
+	}
+	return math.Max(20*math.Log10(rms), minEnergy)
+}
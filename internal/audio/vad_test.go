@@ -0,0 +1,69 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// pcm16 生成 d 时长的 16kHz 单声道 PCM：amplitude > 0 时为 440Hz 正弦波，否则为幅度 noise 的白噪声
+func pcm16(d time.Duration, amplitude, noise float64) []byte {
+	n := int(d * 16000 / time.Second)
+	buf := make([]byte, n*2)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < n; i++ {
+		v := (rng.Float64()*2 - 1) * noise
+		if amplitude > 0 {
+			v += amplitude * math.Sin(2*math.Pi*440*float64(i)/16000)
+		}
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(int16(v*32767)))
+	}
+	return buf
+}
+
+func TestVAD(t *testing.T) {
+	var stream []byte
+	stream = append(stream, pcm16(time.Second, 0, 0.001)...)
+	stream = append(stream, pcm16(60*time.Millisecond, 0.3, 0.001)...) // 短噪声，不应触发
+	stream = append(stream, pcm16(time.Second, 0, 0.001)...)
+	stream = append(stream, pcm16(time.Second, 0.1, 0.001)...)
+	stream = append(stream, pcm16(time.Second, 0, 0.001)...)
+	stream = append(stream, pcm16(300*time.Millisecond, 0.1, 0.001)...)
+
+	vad, err := NewVAD(CodecOption{SampleRate: 16000, FrameDuration: "20ms"})
+	if err != nil {
+		t.Fatalf("new vad: %v", err)
+	}
+
+	// 按不对齐窗口的大小送入，最后一帧仍在说话
+	var events []VADEvent
+	chunk := 1000
+	for i := 0; i < len(stream); i += chunk {
+		end := min(i+chunk, len(stream))
+		events = append(events, vad.Process(Frame{Payload: stream[i:end], IsLast: end == len(stream)})...)
+	}
+
+	want := []struct {
+		typ VADEventType
+		at  time.Duration
+	}{
+		{VADSpeechStart, 2060 * time.Millisecond},
+		{VADSpeechEnd, 3060 * time.Millisecond},
+		{VADSpeechStart, 4060 * time.Millisecond},
+		{VADSpeechEnd, 4360 * time.Millisecond},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	for i, w := range want {
+		diff := events[i].Time - w.at
+		if events[i].Type != w.typ || diff < -20*time.Millisecond || diff > 20*time.Millisecond {
+			t.Fatalf("event %d = %s at %s, want %s at %s", i, events[i].Type, events[i].Time, w.typ, w.at)
+		}
+	}
+	if vad.Speaking() {
+		t.Fatal("expected reset after last frame")
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"ava/internal/tts"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config 对应 configs/config.example.yaml
+type Config struct {
+	ServiceName string            `yaml:"serviceName"`
+	Port        int               `yaml:"port"`
+	LogLevel    string            `yaml:"logLevel"`
+	TTS         tts.EngineOptions `yaml:"tts"`
+}
+
+// Load 读取并解析 YAML 配置文件，支持 ${ENV} 形式的环境变量替换
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: read %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &cfg); err != nil {
+		return nil, fmt.Errorf("config: parse %s: %w", path, err)
+	}
+	return &cfg, nil
+}
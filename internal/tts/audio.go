@@ -0,0 +1,99 @@
+package tts
+
+import (
+	"ava/internal/audio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gopxl/beep"
+)
+
+// CodecOption 返回 Streamer 缓冲区中音频的编码（由 Engine 决定，每帧 20ms）
+func (s *Streamer) CodecOption() audio.CodecOption {
+	codec := s.codec.Option()
+	codec.FrameDuration = "20ms"
+	return codec
+}
+
+// Frames 在后台消费 Streamer，把合成的音频切成 audio.Frame
+// codec 可选，用于转换编码、位深、声道数或采样率（如电话线路需要的 8kHz PCMU），默认使用 CodecOption。
+// 与交给 Speaker 播放互斥：两者都会消费 Streamer 中的数据
+func (s *Streamer) Frames(codec ...audio.CodecOption) (audio.Stream, error) {
+	target := s.CodecOption()
+	if len(codec) > 0 {
+		target = codec[0].WithDefaults()
+	}
+	var src beep.Streamer = s
+	if target.SampleRate != int(s.format.SampleRate) {
+		r, err := audio.Resample(s, s.format.SampleRate, beep.SampleRate(target.SampleRate))
+		if err != nil {
+			return nil, fmt.Errorf("tts: frames: %w", err)
+		}
+		src = r
+	}
+	return audio.FromBeep(src, target)
+}
+
+// NewStreamerWithDecoder 创建接收 encoding 压缩音频（如 "mp3"、"ogg_opus"）的 Streamer
+// AppendAudio 写入的数据经 audio.NewDecoder 增量解码为 16 位 PCM，解码器需要事先用 audio.RegisterDecoder 注册
+func NewStreamerWithDecoder(encoding string, sampleRate beep.SampleRate, channels int) (*Streamer, error) {
+	s, err := NewStreamerWithCodec(audio.CodecOption{
+		Codec:      audio.CodecPCM,
+		SampleRate: int(sampleRate),
+		Channels:   channels,
+		BitDepth:   16,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.decoder, err = audio.NewDecoder(encoding, int(sampleRate), channels, func(samples [][2]float64) {
+		s.appendPCM(s.codec.Encode(nil, samples))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tts: streamer: %w", err)
+	}
+	return s, nil
+}
+
+// NewStreamerFromAudio 创建一个由 audio.Stream 供数据的 Streamer，可以交给 StreamQueue 播放
+// 帧的格式由 codec 描述；src 结束时 Streamer 随之结束，Streamer 被 Cancel 时关闭 src
+func NewStreamerFromAudio(src audio.Stream, codec audio.CodecOption) (*Streamer, error) {
+	s, err := NewStreamerWithCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	finished := make(chan struct{})
+	go func() {
+		// Streamer 被取消时关闭 src，使阻塞中的 Read 返回
+		select {
+		case <-s.ctx.Done():
+			src.Close()
+		case <-finished:
+		}
+	}()
+	go func() {
+		defer close(finished)
+		defer src.Close()
+		for {
+			frame, err := src.Read()
+			if s.ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					s.Close()
+				} else {
+					s.CloseWithError(err)
+				}
+				return
+			}
+			s.AppendAudio(frame.Payload)
+			if frame.IsLast {
+				s.Close()
+				return
+			}
+		}
+	}()
+	return s, nil
+}
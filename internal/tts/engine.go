@@ -1,5 +1,13 @@
 package tts
 
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoActiveSession 没有活动 session 时调用 Synthesize
+var ErrNoActiveSession = errors.New("tts: no active session")
+
 // WordTiming 表示一个词的时间信息
 type WordTiming struct {
 	Word       string  `json:"word"`
@@ -14,17 +22,34 @@ type SentenceTiming struct {
 	Words []WordTiming `json:"words"`
 }
 
+// Shift 返回所有词的时间都加上 offset 秒的副本，用于拼接多个 session 的时间戳
+func (t SentenceTiming) Shift(offset float64) SentenceTiming {
+	words := make([]WordTiming, len(t.Words))
+	for i, w := range t.Words {
+		w.StartTime += offset
+		w.EndTime += offset
+		words[i] = w
+	}
+	t.Words = words
+	return t
+}
+
+// Engine 流式语音合成引擎
+// 所有会等待服务端的调用都接收 context：取消 ctx 会立即返回 ctx.Err()，ctx 的 deadline 用于限制单次调用的延迟
+// 如果 ctx 没有 deadline，实现应该使用自己的默认超时
 type Engine interface {
-	Start(emotion string, contextTexts []string) (*Streamer, error) // 启动 session，emotion 参数用于设置情感（可选，推荐使用 contextTexts 替代），contextTexts 用于上下文辅助合成（推荐使用，可通过自然语言描述替代 emotion）
-	Synthesize(text string, contextTexts []string) error            // 合成文本，contextTexts 用于上下文辅助合成（推荐使用，可通过自然语言描述替代 emotion）
-	End() error
-	Close() error // 关闭连接并清理资源
+	Start(ctx context.Context, emotion string, contextTexts []string) (*Streamer, error) // 启动 session，emotion 参数用于设置情感（可选，推荐使用 contextTexts 替代），contextTexts 用于上下文辅助合成（推荐使用，可通过自然语言描述替代 emotion）
+	Synthesize(ctx context.Context, text string, contextTexts []string) error            // 合成文本，contextTexts 用于上下文辅助合成（推荐使用，可通过自然语言描述替代 emotion）
+	End(ctx context.Context) error                                                       // 结束 session，等待服务端合成完剩余文本
+	Cancel(ctx context.Context) error                                                    // 取消 session，服务端立即停止合成，没有活动 session 时直接返回
+	Close() error                                                                        // 关闭连接并清理资源
 }
 
+// EngineInfo 描述一个已注册的引擎，见 Register / Engines
 type EngineInfo struct {
 	Name         string
 	Version      string
 	Description  string
-	Capabilities []string
-	Config       map[string]string
+	Capabilities []string          // 能力列表，如 "streaming"、"timestamp"、"emotion"
+	Config       map[string]string // 支持的配置项名称 -> 说明
 }
@@ -0,0 +1,305 @@
+package tts
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// eventInterval 检查播放位置的间隔，决定词边界事件的延迟
+const eventInterval = 10 * time.Millisecond
+
+// PlaybackEventType 播放事件类型
+type PlaybackEventType int
+
+const (
+	EventSessionStarted PlaybackEventType = iota // 新 session 加入播放队列（Say 的 Start 或 Play）
+	EventFirstAudio                              // session 的第一个样本开始被听到
+	EventWord                                    // 一个词开始播放
+	EventSentenceEnd                             // 一个句子播放完
+	EventUnderrun                                // 缓冲耗尽导致播放中断（合成跟不上播放）
+	EventStopped                                 // 播放被打断（Interrupt / Stop / Close）
+	EventFinished                                // 最后一个样本播放完成
+)
+
+func (t PlaybackEventType) String() string {
+	switch t {
+	case EventSessionStarted:
+		return "session_started"
+	case EventFirstAudio:
+		return "first_audio"
+	case EventWord:
+		return "word"
+	case EventSentenceEnd:
+		return "sentence_end"
+	case EventUnderrun:
+		return "underrun"
+	case EventStopped:
+		return "stopped"
+	case EventFinished:
+		return "finished"
+	default:
+		return "unknown"
+	}
+}
+
+// PlaybackEvent 播放事件
+type PlaybackEvent struct {
+	Type     PlaybackEventType
+	Session  int             // session 序号，从 1 开始递增
+	Time     float64         // 事件在播放时钟上的位置（秒，相对 session 开始的音频），与 WordTiming 的时间一致
+	At       time.Time       // 事件发出的时间
+	Word     *WordTiming     // EventWord 时的词
+	Sentence *SentenceTiming // EventSentenceEnd 时的句子
+	Err      error           // EventFinished 时合成失败的原因，正常结束为 nil
+}
+
+// eventHub 跟踪已提交的 session 的播放位置，向订阅者发送事件
+type eventHub struct {
+	mu      sync.Mutex
+	subs    map[chan PlaybackEvent]struct{}
+	watches []*watch
+	seq     int
+	stop    chan struct{} // 后台检查 goroutine 运行时非 nil
+	closed  bool
+}
+
+// watch 一个 session 的事件进度
+type watch struct {
+	session  int
+	streamer *Streamer
+	started  bool // 已发送 EventFirstAudio
+	sentence int  // 下一个待处理的句子
+	word     int  // sentence 中下一个待发送的词
+	underrun int  // 已发送的 underrun 数
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subs: make(map[chan PlaybackEvent]struct{})}
+}
+
+// subscribe 添加订阅者，有订阅者时启动后台检查
+func (h *eventHub) subscribe(buffer int) (<-chan PlaybackEvent, func()) {
+	if buffer <= 0 {
+		buffer = 64
+	}
+	ch := make(chan PlaybackEvent, buffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if h.stop == nil {
+		// 没有订阅者期间没有检查，先把各 session 推进到当前位置，新订阅者只收到之后的事件
+		h.check()
+	}
+	h.subs[ch] = struct{}{}
+	if h.stop == nil {
+		h.stop = make(chan struct{})
+		go h.run(h.stop)
+	}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if _, ok := h.subs[ch]; !ok {
+				return // 已经随 close 关闭
+			}
+			delete(h.subs, ch)
+			close(ch)
+			if len(h.subs) == 0 && h.stop != nil {
+				close(h.stop)
+				h.stop = nil
+			}
+		})
+	}
+}
+
+// watch 开始跟踪 streamer 并发送 EventSessionStarted
+func (h *eventHub) watch(s *Streamer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	if h.stop == nil {
+		// 没有订阅者时只保留未结束的 session，避免列表无限增长
+		h.watches = pruneWatches(h.watches)
+	}
+	h.seq++
+	h.watches = append(h.watches, &watch{session: h.seq, streamer: s})
+	h.emit(PlaybackEvent{Type: EventSessionStarted, Session: h.seq, At: time.Now()})
+}
+
+// close 最后检查一次各 session（发送 Close 打断产生的 EventStopped），然后关闭所有订阅者的 channel
+func (h *eventHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	if len(h.subs) > 0 {
+		h.check()
+	}
+	if h.stop != nil {
+		close(h.stop)
+		h.stop = nil
+	}
+	for ch := range h.subs {
+		delete(h.subs, ch)
+		close(ch)
+	}
+}
+
+func (h *eventHub) run(stop chan struct{}) {
+	ticker := time.NewTicker(eventInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		h.mu.Lock()
+		if h.stop == stop {
+			h.check()
+		}
+		h.mu.Unlock()
+	}
+}
+
+// check 按各 session 当前的播放位置发送事件，调用方持有 h.mu
+func (h *eventHub) check() {
+	remaining := h.watches[:0]
+	for _, w := range h.watches {
+		if !h.advance(w) {
+			remaining = append(remaining, w)
+		}
+	}
+	clear(h.watches[len(remaining):])
+	h.watches = remaining
+}
+
+// advance 发送 w 到当前播放位置为止的事件，session 结束时返回 true
+func (h *eventHub) advance(w *watch) (ended bool) {
+	s := w.streamer
+	done := false
+	select {
+	case <-s.Done():
+		done = true
+	default:
+	}
+	canceled := done && s.canceled()
+
+	current, _ := s.GetProgress()
+	if done && !canceled {
+		// 正常结束：补齐最后一次检查之后播放完的部分
+		d, _ := s.Duration()
+		current = max(current, d.Seconds())
+	}
+	now := time.Now()
+	event := func(e PlaybackEvent) {
+		e.Session = w.session
+		e.At = now
+		h.emit(e)
+	}
+
+	if !w.started && current > 0 {
+		w.started = true
+		event(PlaybackEvent{Type: EventFirstAudio})
+	}
+
+	// underrun 和词按时间顺序交错发送
+	underruns := s.Underruns()
+	nextUnderrun := func() (float64, bool) {
+		if w.underrun < len(underruns) {
+			if at := underruns[w.underrun].Seconds(); at <= current {
+				return at, true
+			}
+		}
+		return 0, false
+	}
+	timings := s.GetTimings()
+	for w.sentence < len(timings) {
+		sentence := timings[w.sentence]
+		if w.word < len(sentence.Words) {
+			word := sentence.Words[w.word]
+			if word.StartTime > current {
+				break
+			}
+			if at, ok := nextUnderrun(); ok && at <= word.StartTime {
+				w.underrun++
+				event(PlaybackEvent{Type: EventUnderrun, Time: at})
+				continue
+			}
+			w.word++
+			event(PlaybackEvent{Type: EventWord, Time: word.StartTime, Word: &word})
+			continue
+		}
+		end := 0.0
+		if n := len(sentence.Words); n > 0 {
+			end = sentence.Words[n-1].EndTime
+		}
+		if end > current {
+			break
+		}
+		w.sentence++
+		w.word = 0
+		event(PlaybackEvent{Type: EventSentenceEnd, Time: end, Sentence: &sentence})
+	}
+	for {
+		at, ok := nextUnderrun()
+		if !ok {
+			break
+		}
+		w.underrun++
+		event(PlaybackEvent{Type: EventUnderrun, Time: at})
+	}
+
+	if !done {
+		return false
+	}
+	if canceled {
+		event(PlaybackEvent{Type: EventStopped, Time: current})
+		return true
+	}
+	err := s.Err()
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	event(PlaybackEvent{Type: EventFinished, Time: current, Err: err})
+	return true
+}
+
+// emit 非阻塞地发送给所有订阅者，channel 满时丢弃，调用方持有 h.mu
+func (h *eventHub) emit(e PlaybackEvent) {
+	for ch := range h.subs {
+		select {
+		case ch <- e:
+		default:
+			logrus.Warnf("speaker: playback event %s dropped, subscriber is too slow", e.Type)
+		}
+	}
+}
+
+func pruneWatches(watches []*watch) []*watch {
+	remaining := watches[:0]
+	for _, w := range watches {
+		select {
+		case <-w.streamer.Done():
+		default:
+			remaining = append(remaining, w)
+		}
+	}
+	clear(watches[len(remaining):])
+	return remaining
+}
@@ -0,0 +1,86 @@
+package tts
+
+import (
+	"ava/internal/tts/sink"
+	"testing"
+	"time"
+)
+
+func TestSpeakerEvents(t *testing.T) {
+	speaker, err := NewSpeaker(nil, sink.NewNullSink())
+	if err != nil {
+		t.Fatalf("new speaker: %v", err)
+	}
+	defer speaker.Close()
+	events, cancel := speaker.Subscribe(0)
+	defer cancel()
+
+	s := NewStreamer(16000, 1)
+	s.AppendAudio(make([]byte, 3200)) // 100ms
+	s.AddTiming(SentenceTiming{Text: "你", Words: []WordTiming{{Word: "你", StartTime: 0, EndTime: 0.1}}})
+	speaker.Play(s)
+
+	// 第一段播放完之后才收到第二段，中间缓冲耗尽
+	time.Sleep(250 * time.Millisecond)
+	s.AppendAudio(make([]byte, 3200))
+	s.AddTiming(SentenceTiming{Text: "好", Words: []WordTiming{{Word: "好", StartTime: 0.1, EndTime: 0.2}}})
+	s.Close()
+
+	want := []PlaybackEventType{
+		EventSessionStarted, EventFirstAudio,
+		EventWord, EventSentenceEnd,
+		EventUnderrun,
+		EventWord, EventSentenceEnd,
+		EventFinished,
+	}
+	var got []PlaybackEvent
+	timeout := time.After(2 * time.Second)
+	for len(got) < len(want) {
+		select {
+		case e := <-events:
+			got = append(got, e)
+		case <-timeout:
+			t.Fatalf("timeout waiting for events, got %v", got)
+		}
+	}
+	for i, e := range got {
+		if e.Type != want[i] || e.Session != 1 {
+			t.Fatalf("event %d: got %s (session %d), want %s", i, e.Type, e.Session, want[i])
+		}
+	}
+	if got[4].Time != 0.1 || got[5].Word.Word != "好" || got[7].Time != 0.2 || got[7].Err != nil {
+		t.Fatalf("unexpected events: %+v", got)
+	}
+}
+
+func TestSpeakerEventsStoppedOnClose(t *testing.T) {
+	speaker, err := NewSpeaker(nil, sink.NewNullSink())
+	if err != nil {
+		t.Fatalf("new speaker: %v", err)
+	}
+	events, cancel := speaker.Subscribe(0)
+	defer cancel()
+
+	s := NewStreamer(16000, 1)
+	s.AppendAudio(make([]byte, 32000)) // 1s
+	speaker.Play(s)
+	time.Sleep(50 * time.Millisecond)
+	speaker.Close()
+
+	var got []PlaybackEventType
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				if len(got) == 0 || got[len(got)-1] != EventStopped {
+					t.Fatalf("expected EventStopped before channel closed, got %v", got)
+				}
+				return
+			}
+			got = append(got, e.Type)
+		case <-timeout:
+			t.Fatalf("timeout waiting for channel close, got %v", got)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package tts
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Interruption 描述一次被打断的播放，用于让对话历史只保留用户实际听到的内容
+type Interruption struct {
+	Texts       []string // 被打断的 session 提交合成的文本片段
+	PlayedWords []string // 用户已经完整听到的词（按词时间戳计算）
+	PlayedText  string   // PlayedWords 对齐回原文后的文本，保留标点
+	PlayedTime  float64  // 被打断时的播放时间（秒）
+}
+
+// interruption 根据当前播放的 streamer 计算用户听到的内容
+func (s *Speaker) interruption() (Interruption, bool) {
+	streamer := s.streamQueue.CurrentStreamer()
+	if streamer == nil {
+		return Interruption{}, false
+	}
+
+	currentTime, _ := streamer.GetProgress()
+	in := Interruption{
+		Texts:      streamer.GetTexts(),
+		PlayedTime: currentTime,
+	}
+	for _, sentence := range streamer.GetTimings() {
+		for _, w := range sentence.Words {
+			if w.EndTime <= currentTime {
+				in.PlayedWords = append(in.PlayedWords, w.Word)
+			}
+		}
+	}
+	in.PlayedText = AlignPlayed(strings.Join(in.Texts, ""), in.PlayedWords)
+	return in, true
+}
+
+// AlignPlayed 在原文 text 中依次查找已播放的词，返回原文中到最后一个已播放词为止的前缀
+// 紧跟其后的标点也会保留；找不到的词（如服务端改写过的数字）会被跳过
+func AlignPlayed(text string, words []string) string {
+	end := 0
+	for _, w := range words {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+		i := strings.Index(text[end:], w)
+		if i < 0 {
+			continue
+		}
+		end += i + len(w)
+	}
+	if end == 0 {
+		return ""
+	}
+
+	// 带上紧跟的标点
+	for _, r := range text[end:] {
+		if !unicode.IsPunct(r) {
+			break
+		}
+		end += len(string(r))
+	}
+	return text[:end]
+}
@@ -0,0 +1,110 @@
+package tts
+
+import "context"
+
+// LegacyEngine 旧版不带 context 的引擎接口
+type LegacyEngine interface {
+	Start(emotion string, contextTexts []string) (*Streamer, error)
+	Synthesize(text string, contextTexts []string) error
+	End() error
+	Close() error
+}
+
+// FromLegacy 把旧版引擎包装为 Engine
+// 旧版调用本身无法被中断：ctx 取消时立即返回 ctx.Err()，底层调用在后台继续执行直到完成
+func FromLegacy(e LegacyEngine) Engine {
+	return &legacyEngine{e: e}
+}
+
+type legacyEngine struct {
+	e LegacyEngine
+}
+
+func (l *legacyEngine) Start(ctx context.Context, emotion string, contextTexts []string) (*Streamer, error) {
+	type result struct {
+		streamer *Streamer
+		err      error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		s, err := l.e.Start(emotion, contextTexts)
+		ch <- result{s, err}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.streamer, r.err
+	case <-ctx.Done():
+		// session 可能稍后才启动成功，此时没有人消费这个 streamer
+		go func() {
+			if r := <-ch; r.streamer != nil {
+				r.streamer.Cancel()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+func (l *legacyEngine) Synthesize(ctx context.Context, text string, contextTexts []string) error {
+	return runLegacy(ctx, func() error { return l.e.Synthesize(text, contextTexts) })
+}
+
+func (l *legacyEngine) End(ctx context.Context) error {
+	return runLegacy(ctx, l.e.End)
+}
+
+// Cancel 旧版引擎如果实现了 Cancel() error 则调用它，否则退化为 End
+func (l *legacyEngine) Cancel(ctx context.Context) error {
+	if c, ok := l.e.(interface{ Cancel() error }); ok {
+		return runLegacy(ctx, c.Cancel)
+	}
+	return runLegacy(ctx, l.e.End)
+}
+
+func (l *legacyEngine) Close() error {
+	return l.e.Close()
+}
+
+func runLegacy(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ch := make(chan error, 1)
+	go func() { ch <- fn() }()
+	select {
+	case err := <-ch:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ToLegacy 把 Engine 包装为旧版接口，所有调用使用 context.Background()
+// 供尚未迁移到 context 的调用方使用
+func ToLegacy(e Engine) LegacyEngine {
+	return &contextFreeEngine{e: e}
+}
+
+type contextFreeEngine struct {
+	e Engine
+}
+
+func (c *contextFreeEngine) Start(emotion string, contextTexts []string) (*Streamer, error) {
+	return c.e.Start(context.Background(), emotion, contextTexts)
+}
+
+func (c *contextFreeEngine) Synthesize(text string, contextTexts []string) error {
+	return c.e.Synthesize(context.Background(), text, contextTexts)
+}
+
+func (c *contextFreeEngine) End() error {
+	return c.e.End(context.Background())
+}
+
+func (c *contextFreeEngine) Cancel() error {
+	return c.e.Cancel(context.Background())
+}
+
+func (c *contextFreeEngine) Close() error {
+	return c.e.Close()
+}
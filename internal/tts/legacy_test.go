@@ -0,0 +1,42 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type blockingLegacyEngine struct {
+	release chan struct{}
+}
+
+func (e *blockingLegacyEngine) Start(string, []string) (*Streamer, error) {
+	<-e.release
+	return NewStreamer(16000, 1), nil
+}
+func (e *blockingLegacyEngine) Synthesize(string, []string) error { return nil }
+func (e *blockingLegacyEngine) End() error                        { <-e.release; return nil }
+func (e *blockingLegacyEngine) Close() error                      { return nil }
+
+func TestFromLegacyCancel(t *testing.T) {
+	legacy := &blockingLegacyEngine{release: make(chan struct{})}
+	defer close(legacy.release)
+	e := FromLegacy(legacy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := e.Start(ctx, "", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	if err := e.Synthesize(context.Background(), "hi", nil); err != nil {
+		t.Fatalf("synthesize: %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := e.End(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled, got %v", err)
+	}
+}
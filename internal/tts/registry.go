@@ -0,0 +1,167 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// EngineConfig 引擎配置，通常来自 YAML 配置文件中 tts.config 段
+type EngineConfig map[string]any
+
+// String 读取字符串配置，不存在时返回 def
+func (c EngineConfig) String(key, def string) string {
+	v, ok := c[key]
+	if !ok || v == nil {
+		return def
+	}
+	return fmt.Sprint(v)
+}
+
+// Int 读取整数配置，兼容 YAML/JSON 解码出的各种数字类型和数字字符串
+func (c EngineConfig) Int(key string, def int) (int, error) {
+	v, ok := c[key]
+	if !ok || v == nil {
+		return def, nil
+	}
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	case uint64:
+		return int(n), nil
+	case float64:
+		return int(n), nil
+	case string:
+		i, err := strconv.Atoi(n)
+		if err != nil {
+			return def, fmt.Errorf("tts: config %q: %w", key, err)
+		}
+		return i, nil
+	default:
+		return def, fmt.Errorf("tts: config %q: unsupported type %T", key, v)
+	}
+}
+
+// Float 读取浮点数配置
+func (c EngineConfig) Float(key string, def float64) (float64, error) {
+	v, ok := c[key]
+	if !ok || v == nil {
+		return def, nil
+	}
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return def, fmt.Errorf("tts: config %q: %w", key, err)
+		}
+		return f, nil
+	default:
+		return def, fmt.Errorf("tts: config %q: unsupported type %T", key, v)
+	}
+}
+
+// EngineOptions 选择并配置一个引擎，对应 YAML 中的 tts 段
+type EngineOptions struct {
+	Engine string       `yaml:"engine" json:"engine"` // 注册名，如 "tts.volcengine"
+	Config EngineConfig `yaml:"config" json:"config"` // 传给引擎工厂的配置
+}
+
+// EngineFactory 根据配置创建引擎
+type EngineFactory func(ctx context.Context, config EngineConfig) (Engine, error)
+
+type registeredEngine struct {
+	info    EngineInfo
+	factory EngineFactory
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]registeredEngine{}
+)
+
+// Register 注册引擎工厂，通常在引擎包的 init() 中调用
+// info 可选，用于运行时列出引擎能力（EngineInfo.Config 为配置项名称到说明的映射）
+// 重复注册同一名称会 panic
+func Register(name string, factory EngineFactory, info ...EngineInfo) {
+	if factory == nil {
+		panic("tts: Register factory is nil for " + name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[name]; dup {
+		panic("tts: Register called twice for " + name)
+	}
+
+	var engineInfo EngineInfo
+	if len(info) > 0 {
+		engineInfo = info[0]
+	}
+	engineInfo.Name = name
+
+	registry[name] = registeredEngine{info: engineInfo, factory: factory}
+}
+
+// New 根据注册名和配置创建引擎
+func New(ctx context.Context, name string, config EngineConfig) (Engine, error) {
+	registryMu.RLock()
+	r, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("tts: unknown engine %q (registered: %v)", name, registeredNames())
+	}
+	if config == nil {
+		config = EngineConfig{}
+	}
+	return r.factory(ctx, config)
+}
+
+// NewFromOptions 根据配置文件中的 tts 段创建引擎
+func NewFromOptions(ctx context.Context, opts EngineOptions) (Engine, error) {
+	return New(ctx, opts.Engine, opts.Config)
+}
+
+// Lookup 获取已注册引擎的信息
+func Lookup(name string) (EngineInfo, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	r, ok := registry[name]
+	return r.info, ok
+}
+
+// Engines 列出所有已注册引擎的信息，按名称排序
+func Engines() []EngineInfo {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	infos := make([]EngineInfo, 0, len(registry))
+	for _, r := range registry {
+		infos = append(infos, r.info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+func registeredNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,52 @@
+package tts
+
+import (
+	"context"
+	"testing"
+)
+
+type nopEngine struct{ rate int }
+
+func (e *nopEngine) Start(context.Context, string, []string) (*Streamer, error) {
+	return NewStreamer(16000, 1), nil
+}
+func (e *nopEngine) Synthesize(context.Context, string, []string) error { return nil }
+func (e *nopEngine) End(context.Context) error                          { return nil }
+func (e *nopEngine) Cancel(context.Context) error                       { return nil }
+func (e *nopEngine) Close() error                                       { return nil }
+
+func TestRegistry(t *testing.T) {
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, "test.nop")
+		registryMu.Unlock()
+	})
+
+	Register("test.nop", func(ctx context.Context, config EngineConfig) (Engine, error) {
+		rate, err := config.Int("sampleRate", 16000)
+		if err != nil {
+			return nil, err
+		}
+		return &nopEngine{rate: rate}, nil
+	}, EngineInfo{Capabilities: []string{"streaming"}})
+
+	info, ok := Lookup("test.nop")
+	if !ok || info.Name != "test.nop" || len(info.Capabilities) != 1 {
+		t.Fatalf("unexpected info %+v", info)
+	}
+
+	e, err := NewFromOptions(context.Background(), EngineOptions{
+		Engine: "test.nop",
+		Config: EngineConfig{"sampleRate": "24000"},
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if got := e.(*nopEngine).rate; got != 24000 {
+		t.Fatalf("unexpected sample rate %d", got)
+	}
+
+	if _, err := New(context.Background(), "test.missing", nil); err == nil {
+		t.Fatalf("expected error for unknown engine")
+	}
+}
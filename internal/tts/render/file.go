@@ -0,0 +1,148 @@
+package render
+
+import (
+	"ava/internal/tts"
+	"ava/internal/tts/sink"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileFormat 输出文件格式
+type FileFormat int
+
+const (
+	FormatAuto FileFormat = iota // 按扩展名选择：.pcm / .raw 为裸 PCM，其它为 WAV
+	FormatWAV
+	FormatRaw // 裸 PCM16LE，没有文件头
+)
+
+// Options 文件输出配置
+type Options struct {
+	Format      FileFormat
+	TimingsPath string // 时间戳 JSON 路径，默认把音频文件扩展名替换为 .json
+	SkipTimings bool   // 不写时间戳文件
+}
+
+// TimingsFile 时间戳 JSON 的结构
+type TimingsFile struct {
+	Audio      string               `json:"audio"`      // 音频文件名
+	SampleRate int                  `json:"sampleRate"` // 采样率
+	Channels   int                  `json:"channels"`   // 声道数
+	Duration   float64              `json:"duration"`   // 总时长（秒）
+	Sentences  []tts.SentenceTiming `json:"sentences"`
+}
+
+// ToFile 渲染 reqs 并写入 path，WAV 文件头按 Streamer 的 beep.Format 生成
+// 音频先写入同目录的临时文件，成功后才重命名为 path，失败时不会留下不完整的文件
+// 默认同时在旁边写出时间戳 JSON，见 Options
+func ToFile(ctx context.Context, engine tts.Engine, reqs []tts.SayRequest, path string, opts ...Options) (*Result, error) {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	format := opt.Format
+	if format == FormatAuto {
+		format = formatFromPath(path)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("render: create %s: %w", path, err)
+	}
+	renamed := false
+	defer func() {
+		if !renamed {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	// 格式要等第一个 session 开始后才知道，先占位，结束后回填
+	if format == FormatWAV {
+		if _, err := f.Write(make([]byte, sink.WAVHeaderSize)); err != nil {
+			return nil, fmt.Errorf("render: write wav header: %w", err)
+		}
+	}
+
+	w := bufio.NewWriter(f)
+	result, err := Render(ctx, engine, reqs, w)
+	if err != nil {
+		return result, err
+	}
+	if err := w.Flush(); err != nil {
+		return result, fmt.Errorf("render: write %s: %w", path, err)
+	}
+
+	if format == FormatWAV {
+		dataSize := int64(result.Samples) * int64(result.Format.Width())
+		if dataSize > math.MaxUint32-36 {
+			return result, fmt.Errorf("render: audio too large for wav (%d bytes)", dataSize)
+		}
+		if _, err := f.WriteAt(sink.WAVHeader(result.Format, uint32(dataSize)), 0); err != nil {
+			return result, fmt.Errorf("render: write wav header: %w", err)
+		}
+	}
+	// CreateTemp 创建的文件只有属主可读写，改成和时间戳文件一样的权限
+	if err := f.Chmod(0o644); err != nil {
+		return result, fmt.Errorf("render: chmod %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return result, fmt.Errorf("render: close %s: %w", path, err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return result, fmt.Errorf("render: rename %s: %w", path, err)
+	}
+	renamed = true
+
+	if !opt.SkipTimings {
+		timingsPath := opt.TimingsPath
+		if timingsPath == "" {
+			timingsPath = strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+		}
+		if err := writeTimings(timingsPath, filepath.Base(path), result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// TextToFile 把一段文本作为一个 session 渲染到文件
+func TextToFile(ctx context.Context, engine tts.Engine, text, path string, opts ...Options) (*Result, error) {
+	return ToFile(ctx, engine, []tts.SayRequest{{Text: text, Start: true, End: true}}, path, opts...)
+}
+
+func writeTimings(path, audio string, result *Result) error {
+	sentences := result.Timings
+	if sentences == nil {
+		sentences = []tts.SentenceTiming{}
+	}
+	data, err := json.MarshalIndent(TimingsFile{
+		Audio:      audio,
+		SampleRate: int(result.Format.SampleRate),
+		Channels:   result.Format.NumChannels,
+		Duration:   result.Duration().Seconds(),
+		Sentences:  sentences,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("render: encode timings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("render: write timings: %w", err)
+	}
+	return nil
+}
+
+func formatFromPath(path string) FileFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pcm", ".raw":
+		return FormatRaw
+	default:
+		return FormatWAV
+	}
+}
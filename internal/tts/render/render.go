@@ -0,0 +1,195 @@
+// Package render 离线驱动 tts.Engine 合成完整音频，输出 WAV / 裸 PCM 文件和时间戳 JSON，
+// 用于批量生成旁白等不需要实时播放的场景
+package render
+
+import (
+	"ava/internal/tts"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gopxl/beep"
+)
+
+// Result 渲染结果
+type Result struct {
+	Format  beep.Format
+	Samples int                  // 写出的样本帧数
+	Timings []tts.SentenceTiming // 所有句子的时间戳，多个 session 时已按输出顺序累加偏移
+}
+
+// Duration 音频总时长
+func (r *Result) Duration() time.Duration {
+	if r.Format.SampleRate == 0 {
+		return 0
+	}
+	return r.Format.SampleRate.D(r.Samples)
+}
+
+// pollInterval streamer 暂时没有数据时的等待间隔
+const pollInterval = 5 * time.Millisecond
+
+// Render 依次执行 reqs（语义与 Speaker.Say 相同），把合成的音频以 PCM16LE 写入 w
+// 未显式 End 的 session 会在最后结束；所有 session 的音频格式必须一致
+func Render(ctx context.Context, engine tts.Engine, reqs []tts.SayRequest, w io.Writer) (*Result, error) {
+	r := &renderer{engine: engine, w: w, result: &Result{}}
+	if err := r.run(ctx, reqs); err != nil {
+		return r.result, err
+	}
+	return r.result, nil
+}
+
+// Text 把一段文本作为一个 session 渲染
+func Text(ctx context.Context, engine tts.Engine, text string, w io.Writer) (*Result, error) {
+	return Render(ctx, engine, []tts.SayRequest{{Text: text, Start: true, End: true}}, w)
+}
+
+type renderer struct {
+	engine tts.Engine
+	w      io.Writer
+	result *Result
+
+	session *session // 当前 session，没有时为 nil
+}
+
+// session 一个 session 的 streamer 以及把它写入输出的 goroutine
+type session struct {
+	streamer *tts.Streamer
+	offset   float64 // session 在输出中的起始时间（秒）
+	cancel   context.CancelFunc
+	done     chan struct{} // 写入 goroutine 退出后关闭
+	err      error         // 写入 goroutine 的结果，done 关闭后可读
+}
+
+func (r *renderer) run(ctx context.Context, reqs []tts.SayRequest) (err error) {
+	defer func() {
+		if err != nil && r.session != nil {
+			// 出错时取消服务端合成，并等待写入 goroutine 退出
+			_ = r.engine.Cancel(context.Background())
+			r.session.cancel()
+			<-r.session.done
+			r.session = nil
+		}
+	}()
+
+	for _, req := range reqs {
+		if req.Start {
+			if r.session != nil {
+				if err := r.end(ctx); err != nil {
+					return err
+				}
+			}
+			if err := r.start(ctx, req); err != nil {
+				return err
+			}
+		}
+
+		if req.Text != "" {
+			if err := r.engine.Synthesize(ctx, req.Text, req.ContextTexts); err != nil {
+				return fmt.Errorf("render: synthesize: %w", err)
+			}
+		}
+
+		if req.End && r.session != nil {
+			if err := r.end(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.session != nil {
+		return r.end(ctx)
+	}
+	return nil
+}
+
+func (r *renderer) start(ctx context.Context, req tts.SayRequest) error {
+	streamer, err := r.engine.Start(ctx, req.Emotion, req.ContextTexts)
+	if err != nil {
+		return fmt.Errorf("render: start session: %w", err)
+	}
+
+	format := streamer.Format()
+	if r.result.Format.SampleRate == 0 {
+		r.result.Format = format
+	} else if format != r.result.Format {
+		streamer.Cancel()
+		_ = r.engine.Cancel(ctx)
+		return fmt.Errorf("render: session format %+v differs from %+v", format, r.result.Format)
+	}
+
+	copyCtx, cancel := context.WithCancel(ctx)
+	r.session = &session{
+		streamer: streamer,
+		offset:   format.SampleRate.D(r.result.Samples).Seconds(),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go func(s *session) {
+		s.err = r.copy(copyCtx, s.streamer)
+		close(s.done)
+	}(r.session)
+	return nil
+}
+
+// end 结束当前 session，等待音频全部写出后收集时间戳
+func (r *renderer) end(ctx context.Context) error {
+	s := r.session
+	select {
+	case <-s.done:
+		if s.err != nil {
+			// 写入已经失败，不再等待服务端合成完剩余文本，r.session 保留给 run 取消服务端 session
+			return s.err
+		}
+	default:
+	}
+	if err := r.engine.End(ctx); err != nil {
+		return fmt.Errorf("render: end session: %w", err)
+	}
+	<-s.done
+	if s.err != nil {
+		return s.err
+	}
+	s.cancel()
+	r.session = nil
+
+	for _, timing := range s.streamer.GetTimings() {
+		r.result.Timings = append(r.result.Timings, timing.Shift(s.offset))
+	}
+	return nil
+}
+
+// copy 把 streamer 的样本编码为 PCM16LE 写入输出，直到 streamer 结束
+func (r *renderer) copy(ctx context.Context, streamer *tts.Streamer) error {
+	format := streamer.Format()
+	samples := make([][2]float64, 1024)
+	buf := make([]byte, len(samples)*format.Width())
+
+	for {
+		n, ok := streamer.Stream(samples)
+		if n > 0 {
+			for i := 0; i < n; i++ {
+				format.EncodeSigned(buf[i*format.Width():], samples[i])
+			}
+			if _, err := r.w.Write(buf[:n*format.Width()]); err != nil {
+				return fmt.Errorf("render: write audio: %w", err)
+			}
+			r.result.Samples += n
+		}
+		if !ok {
+			if err := streamer.Err(); err != nil && !errors.Is(err, io.EOF) {
+				return fmt.Errorf("render: stream: %w", err)
+			}
+			return nil
+		}
+		if n == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pollInterval):
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package render_test
+
+import (
+	"ava/internal/tts"
+	"ava/internal/tts/render"
+	"ava/internal/tts/volc"
+	"ava/internal/tts/volc/volctest"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestToFile(t *testing.T) {
+	srv := volctest.NewServer(nil)
+	defer srv.Close()
+
+	engine, err := volc.NewVolcEngineWithConfig(context.Background(), volc.Config{
+		Endpoint: srv.URL,
+		Auth:     volc.AuthConfig{AccessKey: "test-access", AppKey: "test-app"},
+		Voice:    volc.NewVoiceConfig(&volc.VoiceProfile{VoiceType: "test_voice"}),
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	defer engine.Close()
+
+	path := filepath.Join(t.TempDir(), "narration.wav")
+	result, err := render.ToFile(context.Background(), engine, []tts.SayRequest{
+		{Text: "你好", Start: true},
+		{Text: "世界", End: true},
+		{Text: "再见", Start: true, End: true},
+	}, path)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	wantSamples := 6 * int(volctest.CharDuration*16000/time.Second)
+	if result.Samples != wantSamples {
+		t.Fatalf("unexpected samples, got=%d want=%d", result.Samples, wantSamples)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read wav: %v", err)
+	}
+	if string(data[0:4]) != "RIFF" || len(data) != 44+wantSamples*2 {
+		t.Fatalf("unexpected wav, size=%d", len(data))
+	}
+	if size := binary.LittleEndian.Uint32(data[40:44]); size != uint32(wantSamples*2) {
+		t.Fatalf("unexpected data size %d", size)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(filepath.Dir(path), "narration.json"))
+	if err != nil {
+		t.Fatalf("read timings: %v", err)
+	}
+	var timings render.TimingsFile
+	if err := json.Unmarshal(raw, &timings); err != nil {
+		t.Fatalf("decode timings: %v", err)
+	}
+	if timings.Audio != "narration.wav" || timings.SampleRate != 16000 || len(timings.Sentences) != 3 {
+		t.Fatalf("unexpected timings: %+v", timings)
+	}
+	// 第二个 session 的时间戳接在第一个 session 之后
+	if start := timings.Sentences[2].Words[0].StartTime; start != (4 * volctest.CharDuration).Seconds() {
+		t.Fatalf("unexpected offset %v", start)
+	}
+}
+
+func TestToFileRemovesPartialFile(t *testing.T) {
+	// 第一段文本合成后 session 失败，此时已经写出部分音频
+	srv := volctest.NewServer(func(s *volctest.Session) {
+		if err := s.SendStarted(); err != nil {
+			return
+		}
+		if err := s.Speak(<-s.Texts()); err != nil {
+			return
+		}
+		_ = s.SendFailed(55000000, "internal error")
+	})
+	defer srv.Close()
+
+	engine, err := volc.NewVolcEngineWithConfig(context.Background(), volc.Config{
+		Endpoint: srv.URL,
+		Auth:     volc.AuthConfig{AccessKey: "test-access", AppKey: "test-app"},
+		Voice:    volc.NewVoiceConfig(&volc.VoiceProfile{VoiceType: "test_voice"}),
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	defer engine.Close()
+
+	dir := t.TempDir()
+	_, err = render.TextToFile(context.Background(), engine, "你好", filepath.Join(dir, "narration.wav"))
+	if err == nil {
+		t.Fatal("expected render error")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no files left after failure, got %v", entries)
+	}
+}
+
+// failingWriter 写入时总是失败
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestRenderWriteErrorReleasesSession(t *testing.T) {
+	srv := volctest.NewServer(nil)
+	defer srv.Close()
+
+	engine, err := volc.NewVolcEngineWithConfig(context.Background(), volc.Config{
+		Endpoint: srv.URL,
+		Auth:     volc.AuthConfig{AccessKey: "test-access", AppKey: "test-app"},
+		Voice:    volc.NewVoiceConfig(&volc.VoiceProfile{VoiceType: "test_voice"}),
+	})
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	defer engine.Close()
+
+	ctx := context.Background()
+	if _, err := render.Text(ctx, engine, "你好", failingWriter{}); err == nil {
+		t.Fatal("expected write error")
+	}
+	// engine 可以继续渲染
+	var buf bytes.Buffer
+	result, err := render.Text(ctx, engine, "世界", &buf)
+	if err != nil {
+		t.Fatalf("render after write error: %v", err)
+	}
+	if result.Samples == 0 || buf.Len() != result.Samples*2 {
+		t.Fatalf("unexpected result: samples=%d bytes=%d", result.Samples, buf.Len())
+	}
+}
@@ -0,0 +1,211 @@
+package tts
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// SegmenterConfig 流式分句配置
+type SegmenterConfig struct {
+	MinClauseLength int           // 在逗号等子句标点处切分所需的最少字符数，默认 6；句末标点总是切分
+	MaxLength       int           // 没有遇到标点时，累积超过该字符数强制切分，默认 60
+	MaxLatency      time.Duration // 第一个片段到达后最多等待多久就输出已有内容，默认 800ms，负数表示不限制
+}
+
+// DefaultSegmenterConfig 返回默认分句配置
+func DefaultSegmenterConfig() SegmenterConfig {
+	return SegmenterConfig{
+		MinClauseLength: 6,
+		MaxLength:       60,
+		MaxLatency:      800 * time.Millisecond,
+	}
+}
+
+// Segmenter 缓冲 LLM 逐 token 输出的文本片段，在句子/子句边界处整段输出
+// 避免每个 token 都发送一次 TaskRequest，同时让第一句话尽快开始合成
+type Segmenter struct {
+	cfg  SegmenterConfig
+	emit func(text string)
+
+	mu    sync.Mutex // 同时保证 emit 按顺序串行调用
+	buf   []rune
+	timer *time.Timer
+	gen   int // 每次输出后递增，使过期的 timer 失效
+}
+
+// NewSegmenter 创建分句器，每切出一段调用一次 emit，cfg 可选
+func NewSegmenter(emit func(text string), cfg ...SegmenterConfig) *Segmenter {
+	c := DefaultSegmenterConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.MinClauseLength <= 0 {
+		c.MinClauseLength = DefaultSegmenterConfig().MinClauseLength
+	}
+	if c.MaxLength <= 0 {
+		c.MaxLength = DefaultSegmenterConfig().MaxLength
+	}
+	if c.MaxLatency == 0 {
+		c.MaxLatency = DefaultSegmenterConfig().MaxLatency
+	}
+	return &Segmenter{cfg: c, emit: emit}
+}
+
+// Write 追加一个文本片段，遇到边界时同步调用 emit
+func (s *Segmenter) Write(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buf = append(s.buf, []rune(text)...)
+	for {
+		cut := s.cutPoint()
+		if cut <= 0 {
+			break
+		}
+		s.emitLocked(cut)
+	}
+	s.armTimer()
+}
+
+// Flush 输出缓冲中剩余的文本，通常在 session 结束前调用
+func (s *Segmenter) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emitLocked(len(s.buf))
+}
+
+// Reset 丢弃缓冲中的文本，用于打断
+func (s *Segmenter) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buf = s.buf[:0]
+	s.stopTimer()
+}
+
+func (s *Segmenter) onTimeout(gen int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if gen != s.gen || len(s.buf) == 0 {
+		return
+	}
+	// 超时优先在空白处切分，避免切断英文单词
+	cut := len(s.buf)
+	if i := lastSpace(s.buf); i > 0 {
+		cut = i
+	}
+	s.emitLocked(cut)
+	s.armTimer()
+}
+
+// armTimer 缓冲中有未输出的文本时开始计时
+func (s *Segmenter) armTimer() {
+	if s.timer != nil || len(s.buf) == 0 || s.cfg.MaxLatency < 0 {
+		return
+	}
+	gen := s.gen
+	s.timer = time.AfterFunc(s.cfg.MaxLatency, func() { s.onTimeout(gen) })
+}
+
+// emitLocked 输出 buf[:n]，调用方必须持有 mu
+func (s *Segmenter) emitLocked(n int) {
+	s.stopTimer()
+	if n <= 0 {
+		return
+	}
+	text := strings.TrimSpace(string(s.buf[:n]))
+	rest := s.buf[n:]
+	for len(rest) > 0 && unicode.IsSpace(rest[0]) {
+		rest = rest[1:]
+	}
+	s.buf = append(s.buf[:0], rest...)
+	if text != "" {
+		s.emit(text)
+	}
+}
+
+func (s *Segmenter) stopTimer() {
+	s.gen++
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// cutPoint 返回第一个可以切分的位置（切分点之前的字符数），没有时返回 0
+func (s *Segmenter) cutPoint() int {
+	buf := s.buf
+	for i, r := range buf {
+		switch {
+		case isSentenceEnd(r):
+		case isAmbiguousEnd(r):
+			// '.' 等需要看下一个字符：后面是空白才算句末，避免切断 3.14、e.g.
+			if i+1 >= len(buf) {
+				return s.forcedCut()
+			}
+			if !unicode.IsSpace(buf[i+1]) {
+				continue
+			}
+		case isClauseEnd(r):
+			if i+1 < s.cfg.MinClauseLength {
+				continue
+			}
+			// 1,000 / 10:30 中的标点不切分
+			if (r == ',' || r == ':') && i+1 < len(buf) && unicode.IsDigit(buf[i+1]) {
+				continue
+			}
+		default:
+			continue
+		}
+		return skipClosers(buf, i+1)
+	}
+	return s.forcedCut()
+}
+
+// forcedCut 超过最大长度时在最后一个空白或子句标点处切分，都没有时直接截断
+func (s *Segmenter) forcedCut() int {
+	if len(s.buf) < s.cfg.MaxLength {
+		return 0
+	}
+	window := s.buf[:s.cfg.MaxLength]
+	for i := len(window) - 1; i > 0; i-- {
+		if isClauseEnd(window[i]) {
+			return i + 1
+		}
+	}
+	// 第 MaxLength 个字符之后正好是空白时也可以在这里切分
+	if i := lastSpace(s.buf[:min(len(s.buf), s.cfg.MaxLength+1)]); i > 0 {
+		return i
+	}
+	return s.cfg.MaxLength
+}
+
+// skipClosers 把紧跟在标点后的右引号、右括号以及连续的句末标点（……、？！）归入当前句子
+func skipClosers(buf []rune, i int) int {
+	for i < len(buf) && (isSentenceEnd(buf[i]) || strings.ContainsRune(`"'”’）)】」』》`, buf[i])) {
+		i++
+	}
+	return i
+}
+
+func lastSpace(buf []rune) int {
+	for i := len(buf) - 1; i > 0; i-- {
+		if unicode.IsSpace(buf[i]) {
+			return i
+		}
+	}
+	return 0
+}
+
+func isSentenceEnd(r rune) bool {
+	return strings.ContainsRune("。！？；…!?;\n", r)
+}
+
+func isAmbiguousEnd(r rune) bool {
+	return r == '.'
+}
+
+func isClauseEnd(r rune) bool {
+	return strings.ContainsRune("，、：,:", r)
+}
@@ -0,0 +1,95 @@
+package tts
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSegmenter(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    SegmenterConfig
+		tokens []string
+		want   []string // Flush 之前输出的片段
+	}{
+		{
+			name:   "chinese sentences and clauses",
+			tokens: []string{"你好", "呀！今天", "天气不错，", "适合", "出去走走……", "“真的吗？”", "她"},
+			want:   []string{"你好呀！", "今天天气不错，", "适合出去走走……", "“真的吗？”"},
+		},
+		{
+			name:   "short clause is not cut",
+			tokens: []string{"嗯，", "好的。"},
+			want:   []string{"嗯，好的。"},
+		},
+		{
+			name:   "english period needs trailing space",
+			tokens: []string{"Pi is 3", ".14 roughly", ". Next"},
+			want:   []string{"Pi is 3.14 roughly."},
+		},
+		{
+			name:   "max length",
+			cfg:    SegmenterConfig{MaxLength: 10, MaxLatency: -1},
+			tokens: []string{"one two three four five"},
+			want:   []string{"one two", "three four"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			cfg := DefaultSegmenterConfig()
+			if tt.cfg.MaxLength > 0 {
+				cfg = tt.cfg
+			}
+			s := NewSegmenter(func(text string) { got = append(got, text) }, cfg)
+			for _, tok := range tt.tokens {
+				s.Write(tok)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("unexpected segments\n got=%q\nwant=%q", got, tt.want)
+			}
+			s.Reset()
+		})
+	}
+}
+
+func TestSegmenterMaxLatency(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	s := NewSegmenter(func(text string) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, text)
+	}, SegmenterConfig{MaxLatency: 20 * time.Millisecond})
+
+	s.Write("没有标点的")
+	s.Write("开头")
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(got, []string{"没有标点的开头"}) {
+		t.Fatalf("unexpected segments %q", got)
+	}
+}
+
+func TestTagParserSplitEndTag(t *testing.T) {
+	var text string
+	ended := false
+	p := NewTagParser()
+	p.RegisterTag("say", TagCallbacks{
+		OnMiddle: func(s string) { text += s },
+		OnEnd:    func() { ended = true },
+	})
+	for _, tok := range []string{"<say>", "你好", "<", "/say", ">"} {
+		p.Feed(tok)
+	}
+	if text != "你好" || !ended {
+		t.Fatalf("unexpected result text=%q ended=%v", text, ended)
+	}
+}
@@ -0,0 +1,21 @@
+package tts
+
+import (
+	"time"
+
+	"github.com/gopxl/beep"
+)
+
+// AudioSink 表示音频输出端（本地声卡、内存、文件、网络等）
+// Speaker 在构造时把 StreamQueue 交给 Sink，由 Sink 按自己的节奏拉取音频
+type AudioSink interface {
+	Format() beep.Format        // 输出格式（采样率、声道数、位深）
+	Play(s beep.Streamer) error // 开始从 s 拉取音频，非阻塞，只能调用一次
+	Close() error               // 停止拉取并释放资源
+}
+
+// LatencySink 可选接口：报告已经从 Play 的 streamer 拉取、但还没有被听到的音频时长（缓冲深度 + 输出延迟）
+// Speaker 据此校正播放进度，使 Progress 和打断时的已播放文本与用户实际听到的一致
+type LatencySink interface {
+	Latency() time.Duration
+}
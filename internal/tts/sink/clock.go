@@ -0,0 +1,41 @@
+package sink
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 估算已写出但还没有被听到的音频时长（sink 缓冲深度 + 固定输出延迟）
+// 假设写出的音频按实时速度连续播放；数据中断（欠载）后从下一次写出时重新计时
+type Clock struct {
+	delay time.Duration // 缓冲之外的固定输出延迟
+
+	mu  sync.Mutex
+	end time.Time // 已写出的音频全部播放完的时刻
+}
+
+// NewClock 创建 Clock，delay 为缓冲之外的固定输出延迟（如声卡、网络）
+func NewClock(delay time.Duration) *Clock {
+	return &Clock{delay: max(delay, 0)}
+}
+
+// Advance 记录写出了 d 时长的音频
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if c.end.Before(now) {
+		c.end = now
+	}
+	c.end = c.end.Add(d)
+}
+
+// Latency 返回已写出但还没有被听到的音频时长，实现 tts.LatencySink
+func (c *Clock) Latency() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.end.IsZero() {
+		return 0
+	}
+	return max(time.Until(c.end.Add(c.delay)), 0)
+}
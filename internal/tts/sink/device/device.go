@@ -0,0 +1,101 @@
+// Package device 通过 beep/speaker 把音频输出到本地声卡
+// 依赖 cgo 和系统音频库（Linux 下为 ALSA），因此与 sink 包分开，无声卡环境不必引入
+package device
+
+import (
+	"ava/internal/tts/sink"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gopxl/beep"
+	"github.com/gopxl/beep/speaker"
+)
+
+// Sink 本地声卡输出端，beep/speaker 是全局单例，一个进程只能创建一个
+type Sink struct {
+	format     beep.Format
+	bufferSize time.Duration
+	clock      *sink.Clock // 按声卡拉取的样本估算缓冲中还没播放的时长
+
+	mu      sync.Mutex
+	started bool
+}
+
+// NewSink 创建声卡输出端，cfg 可选，Chunk 作为声卡缓冲时长（默认 100ms），Latency 为声卡缓冲之外的输出延迟
+func NewSink(cfg ...sink.Config) *Sink {
+	c := sink.DefaultConfig()
+	c.Chunk = time.Second / 10
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.SampleRate <= 0 {
+		c.SampleRate = 16000
+	}
+	if c.Chunk <= 0 {
+		c.Chunk = time.Second / 10
+	}
+
+	return &Sink{
+		format: beep.Format{
+			SampleRate:  c.SampleRate,
+			NumChannels: 2, // beep/speaker 固定输出双声道
+			Precision:   2,
+		},
+		bufferSize: c.Chunk,
+		clock:      sink.NewClock(c.Latency),
+	}
+}
+
+// Format 实现 tts.AudioSink
+func (s *Sink) Format() beep.Format {
+	return s.format
+}
+
+// Play 初始化声卡并开始播放
+func (s *Sink) Play(st beep.Streamer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return errors.New("device: already playing")
+	}
+
+	sr := s.format.SampleRate
+	if err := speaker.Init(sr, sr.N(s.bufferSize)); err != nil {
+		return fmt.Errorf("device: init speaker: %w", err)
+	}
+	speaker.Play(&clockStreamer{Streamer: st, rate: sr, clock: s.clock})
+	s.started = true
+	return nil
+}
+
+// Latency 实现 tts.LatencySink，返回声卡缓冲中还没有播放出去的音频时长
+func (s *Sink) Latency() time.Duration {
+	return s.clock.Latency()
+}
+
+// Close 停止声卡播放
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		speaker.Close()
+		s.started = false
+	}
+	return nil
+}
+
+// clockStreamer 记录声卡拉取的样本数
+type clockStreamer struct {
+	beep.Streamer
+	rate  beep.SampleRate
+	clock *sink.Clock
+}
+
+func (c *clockStreamer) Stream(samples [][2]float64) (int, bool) {
+	n, ok := c.Streamer.Stream(samples)
+	// beep/speaker 每次拉取整个缓冲，没有数据的部分以静音播放，同样占用播放时间
+	c.clock.Advance(c.rate.D(len(samples)))
+	return n, ok
+}
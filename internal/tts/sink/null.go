@@ -0,0 +1,27 @@
+package sink
+
+import "github.com/gopxl/beep"
+
+// NullSink 按实时速度拉取并丢弃音频，用于没有声卡的环境（容器、CI）
+// 播放进度、Stop 等行为与真实声卡一致
+type NullSink struct {
+	pump
+}
+
+// NewNullSink 创建空输出端，cfg 可选，Realtime 总是为 true
+func NewNullSink(cfg ...Config) *NullSink {
+	c := resolveConfig(cfg)
+	c.Realtime = true
+	return &NullSink{pump: newPump(c)}
+}
+
+// Play 实现 tts.AudioSink
+func (s *NullSink) Play(st beep.Streamer) error {
+	return s.start(st, func([]byte) error { return nil })
+}
+
+// Close 实现 tts.AudioSink
+func (s *NullSink) Close() error {
+	s.stop()
+	return nil
+}
@@ -0,0 +1,195 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gopxl/beep"
+)
+
+var ErrAlreadyPlaying = errors.New("sink: already playing")
+
+// Config 输出端配置
+type Config struct {
+	SampleRate beep.SampleRate // 输出采样率，默认 16000
+	Channels   int             // 输出声道数，默认 1
+	Chunk      time.Duration   // 每次从 streamer 拉取的时长，默认 20ms
+	Realtime   bool            // 是否按实时速度拉取（模拟声卡节奏），否则尽快拉取
+	Latency    time.Duration   // 缓冲之外的固定输出延迟（如对端缓冲、网络），用于校正播放进度，只对实时模式有效
+}
+
+// DefaultConfig 返回默认输出配置
+func DefaultConfig() Config {
+	return Config{
+		SampleRate: 16000,
+		Channels:   1,
+		Chunk:      20 * time.Millisecond,
+	}
+}
+
+func resolveConfig(cfg []Config) Config {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.SampleRate <= 0 {
+		c.SampleRate = 16000
+	}
+	if c.Channels <= 0 {
+		c.Channels = 1
+	}
+	if c.Chunk <= 0 {
+		c.Chunk = 20 * time.Millisecond
+	}
+	return c
+}
+
+// pump 从 beep.Streamer 拉取样本，编码为 PCM16LE 后交给 write
+// 各 sink 内嵌 pump，只需要提供 write 实现
+type pump struct {
+	cfg    Config
+	format beep.Format
+	clock  *Clock // 实时模式下估算输出延迟，非实时模式为 nil
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	err     error
+	started bool
+}
+
+func newPump(cfg Config) pump {
+	var clock *Clock
+	if cfg.Realtime {
+		clock = NewClock(cfg.Latency)
+	}
+	return pump{
+		cfg: cfg,
+		format: beep.Format{
+			SampleRate:  cfg.SampleRate,
+			NumChannels: cfg.Channels,
+			Precision:   2,
+		},
+		clock: clock,
+	}
+}
+
+// Format 实现 tts.AudioSink
+func (p *pump) Format() beep.Format {
+	return p.format
+}
+
+// Latency 实现 tts.LatencySink：实时模式下返回已写出但还没有播放完的音频时长，
+// 非实时模式下数据写出即视为已播放，返回 0
+func (p *pump) Latency() time.Duration {
+	if p.clock == nil {
+		return 0
+	}
+	return p.clock.Latency()
+}
+
+// Err 返回写出过程中遇到的错误
+func (p *pump) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+func (p *pump) start(s beep.Streamer, write func([]byte) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.started {
+		return ErrAlreadyPlaying
+	}
+	p.started = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan struct{})
+	go p.run(ctx, s, write)
+	return nil
+}
+
+// stop 停止拉取并等待 goroutine 退出
+func (p *pump) stop() {
+	p.mu.Lock()
+	cancel, done := p.cancel, p.done
+	p.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+func (p *pump) run(ctx context.Context, s beep.Streamer, write func([]byte) error) {
+	defer close(p.done)
+
+	n := p.cfg.SampleRate.N(p.cfg.Chunk)
+	samples := make([][2]float64, n)
+	buf := make([]byte, n*p.format.Width())
+
+	ticker := time.NewTicker(p.cfg.Chunk)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		filled, ended := fill(s, samples)
+		if filled > 0 {
+			size := encode(p.format, buf, samples[:filled])
+			if err := write(buf[:size]); err != nil {
+				p.mu.Lock()
+				p.err = err
+				p.mu.Unlock()
+				return
+			}
+			if p.clock != nil {
+				p.clock.Advance(p.cfg.SampleRate.D(filled))
+			}
+		}
+		if ended {
+			return
+		}
+
+		// 实时模式每个 chunk 等待一次；非实时模式只在没有数据时等待，避免空转
+		if p.cfg.Realtime || filled == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// fill 尽量填满 samples，返回填充的样本数以及 streamer 是否已结束
+func fill(s beep.Streamer, samples [][2]float64) (filled int, ended bool) {
+	for filled < len(samples) {
+		n, ok := s.Stream(samples[filled:])
+		filled += n
+		if !ok {
+			return filled, true
+		}
+		if n == 0 {
+			break
+		}
+	}
+	return filled, false
+}
+
+// encode 把样本编码为有符号小端 PCM，返回写入的字节数
+func encode(format beep.Format, buf []byte, samples [][2]float64) int {
+	width := format.Width()
+	for i, sample := range samples {
+		format.EncodeSigned(buf[i*width:], sample)
+	}
+	return len(samples) * width
+}
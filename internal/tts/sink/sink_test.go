@@ -0,0 +1,140 @@
+package sink
+
+import (
+	"ava/internal/audio"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gopxl/beep"
+)
+
+// constStreamer 输出 n 个值为 v 的样本后结束
+func constStreamer(n int, v float64) beep.Streamer {
+	return beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		if n <= 0 {
+			return 0, false
+		}
+		k := min(n, len(samples))
+		for i := 0; i < k; i++ {
+			samples[i] = [2]float64{v, v}
+		}
+		n -= k
+		return k, true
+	})
+}
+
+func waitDone(t *testing.T, p *pump) {
+	t.Helper()
+	select {
+	case <-p.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("pump did not finish")
+	}
+}
+
+func TestMemorySink(t *testing.T) {
+	s := NewMemorySink()
+	if err := s.Play(constStreamer(1000, 0.5)); err != nil {
+		t.Fatalf("play: %v", err)
+	}
+	waitDone(t, &s.pump)
+
+	got := s.Bytes()
+	if len(got) != 1000*2 {
+		t.Fatalf("unexpected size, got=%d want=%d", len(got), 2000)
+	}
+	if v := int16(binary.LittleEndian.Uint16(got)); v != 16383 {
+		t.Fatalf("unexpected sample, got=%d want=%d", v, 16383)
+	}
+
+	if err := s.Play(constStreamer(1, 0)); err != ErrAlreadyPlaying {
+		t.Fatalf("expected ErrAlreadyPlaying, got %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestWAVFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.wav")
+	cfg := DefaultConfig()
+	cfg.SampleRate = 24000
+
+	s, err := NewWAVFileSink(path, cfg)
+	if err != nil {
+		t.Fatalf("new wav sink: %v", err)
+	}
+	if err := s.Play(constStreamer(480, 0.25)); err != nil {
+		t.Fatalf("play: %v", err)
+	}
+	waitDone(t, &s.pump)
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read wav: %v", err)
+	}
+	if len(data) != WAVHeaderSize+480*2 {
+		t.Fatalf("unexpected file size %d", len(data))
+	}
+	if string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		t.Fatalf("invalid wav magic")
+	}
+	if sr := binary.LittleEndian.Uint32(data[24:28]); sr != 24000 {
+		t.Fatalf("unexpected sample rate %d", sr)
+	}
+	if size := binary.LittleEndian.Uint32(data[40:44]); size != 480*2 {
+		t.Fatalf("unexpected data size %d", size)
+	}
+}
+
+func TestNullSinkRealtime(t *testing.T) {
+	s := NewNullSink()
+	start := time.Now()
+	// 100ms 的音频，按实时速度拉取至少需要 80ms 左右
+	if err := s.Play(constStreamer(1600, 0)); err != nil {
+		t.Fatalf("play: %v", err)
+	}
+	waitDone(t, &s.pump)
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Fatalf("null sink not paced, elapsed=%v", elapsed)
+	}
+	s.Close()
+}
+
+func TestStreamSink(t *testing.T) {
+	pipe := audio.NewPipe(audio.CodecOption{}, 100)
+	s := NewStreamSink(pipe)
+	// 50ms 的音频按 20ms 切成 3 帧
+	if err := s.Play(constStreamer(800, 0.5)); err != nil {
+		t.Fatalf("play: %v", err)
+	}
+	waitDone(t, &s.pump)
+
+	var sizes []int
+	for pipe.Len() > 0 {
+		frame, err := pipe.Read()
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if frame.IsFirst != (len(sizes) == 0) {
+			t.Fatalf("unexpected IsFirst on frame %d", len(sizes))
+		}
+		sizes = append(sizes, len(frame.Payload))
+	}
+	if len(sizes) != 3 || sizes[0] != 640 || sizes[2] != 320 {
+		t.Fatalf("unexpected frame sizes: %v", sizes)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := pipe.Read(); err != io.EOF {
+		t.Fatalf("expected EOF after close, got %v", err)
+	}
+}
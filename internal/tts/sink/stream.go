@@ -0,0 +1,56 @@
+package sink
+
+import (
+	"ava/internal/audio"
+
+	"github.com/gopxl/beep"
+)
+
+// StreamSink 把 PCM16LE 音频按 Config.Chunk 切成 audio.Frame 写入 audio.Stream，
+// 用于把 Speaker 的输出接到 RTP、WebSocket 等以帧为单位的传输上
+// Stream 缓冲满时会阻塞拉取（背压）
+type StreamSink struct {
+	pump
+	stream audio.Stream
+	first  bool
+}
+
+// NewStreamSink 创建写入 stream 的输出端，cfg 可选（默认非实时）
+func NewStreamSink(stream audio.Stream, cfg ...Config) *StreamSink {
+	return &StreamSink{
+		pump:   newPump(resolveConfig(cfg)),
+		stream: stream,
+		first:  true,
+	}
+}
+
+// Codec 返回写入帧的格式
+func (s *StreamSink) Codec() audio.CodecOption {
+	return audio.CodecOption{
+		Codec:         "pcm",
+		SampleRate:    int(s.cfg.SampleRate),
+		Channels:      s.cfg.Channels,
+		BitDepth:      16,
+		FrameDuration: s.cfg.Chunk.String(),
+	}.WithDefaults()
+}
+
+// Play 实现 tts.AudioSink
+func (s *StreamSink) Play(st beep.Streamer) error {
+	return s.start(st, func(p []byte) error {
+		// pump 会复用 p，帧需要独立的拷贝
+		payload := make([]byte, len(p))
+		copy(payload, p)
+		frame := audio.Frame{Payload: payload, IsFirst: s.first}
+		s.first = false
+		return s.stream.Write(frame)
+	})
+}
+
+// Close 关闭 stream 并停止拉取
+// 先关闭 stream，使阻塞在 Write 上的拉取 goroutine 能够退出
+func (s *StreamSink) Close() error {
+	err := s.stream.Close()
+	s.stop()
+	return err
+}
@@ -0,0 +1,71 @@
+package sink
+
+import (
+	"ava/pkg/websocket"
+	"context"
+	"fmt"
+
+	"github.com/gopxl/beep"
+)
+
+// WebSocketSink 把 PCM16LE 音频以二进制消息推送到 WebSocket 服务端
+type WebSocketSink struct {
+	pump
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	client websocket.WsClient
+}
+
+// NewWebSocketSink 连接 WebSocket 服务端并创建输出端
+// cfg 可选，未提供时按实时速度推送，避免一次性把整段音频塞给对端
+func NewWebSocketSink(ctx context.Context, wsConfig websocket.WSConfig, cfg ...Config) (*WebSocketSink, error) {
+	c := resolveConfig(cfg)
+	if len(cfg) == 0 {
+		c.Realtime = true
+	}
+
+	s := &WebSocketSink{pump: newPump(c)}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	client, err := websocket.NewWsClient(s.ctx, wsConfig)
+	if err != nil {
+		s.cancel()
+		return nil, fmt.Errorf("sink: dial websocket: %w", err)
+	}
+	s.client = client
+
+	return s, nil
+}
+
+// Play 实现 tts.AudioSink
+func (s *WebSocketSink) Play(st beep.Streamer) error {
+	err := s.start(st, func(p []byte) error {
+		// pump 会复用 p，发送队列中需要独立的拷贝
+		frame := make([]byte, len(p))
+		copy(frame, p)
+		return s.client.Send(s.ctx, frame)
+	})
+	if err != nil {
+		return err
+	}
+	go s.discard()
+	return nil
+}
+
+// discard 读取并丢弃对端发来的消息
+// 接收队列满后客户端不再读取连接，ping 和 close 帧也就得不到处理
+func (s *WebSocketSink) discard() {
+	for {
+		if _, err := s.client.Recv(s.ctx); err != nil {
+			return
+		}
+	}
+}
+
+// Close 停止推送并关闭连接
+func (s *WebSocketSink) Close() error {
+	s.stop()
+	s.cancel()
+	return s.client.Close()
+}
@@ -0,0 +1,85 @@
+package sink
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/gopxl/beep"
+)
+
+// WriterSink 把 PCM16LE 音频写入任意 io.Writer
+type WriterSink struct {
+	pump
+	w io.Writer
+}
+
+// NewWriterSink 创建写入 w 的输出端，cfg 可选（默认非实时）
+func NewWriterSink(w io.Writer, cfg ...Config) *WriterSink {
+	return &WriterSink{
+		pump: newPump(resolveConfig(cfg)),
+		w:    w,
+	}
+}
+
+// Play 实现 tts.AudioSink
+func (s *WriterSink) Play(st beep.Streamer) error {
+	return s.start(st, func(p []byte) error {
+		_, err := s.w.Write(p)
+		return err
+	})
+}
+
+// Close 停止拉取，如果 w 实现了 io.Closer 也会一并关闭
+func (s *WriterSink) Close() error {
+	s.stop()
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// MemorySink 把 PCM16LE 音频保存在内存中，主要用于测试
+type MemorySink struct {
+	pump
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// NewMemorySink 创建内存输出端，cfg 可选（默认非实时）
+func NewMemorySink(cfg ...Config) *MemorySink {
+	return &MemorySink{
+		pump: newPump(resolveConfig(cfg)),
+	}
+}
+
+// Play 实现 tts.AudioSink
+func (s *MemorySink) Play(st beep.Streamer) error {
+	return s.start(st, func(p []byte) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		_, err := s.buf.Write(p)
+		return err
+	})
+}
+
+// Bytes 返回目前收到的全部音频数据的拷贝
+func (s *MemorySink) Bytes() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return bytes.Clone(s.buf.Bytes())
+}
+
+// Reset 清空已收到的音频数据
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buf.Reset()
+}
+
+// Close 实现 tts.AudioSink
+func (s *MemorySink) Close() error {
+	s.stop()
+	return nil
+}
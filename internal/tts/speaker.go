@@ -1,18 +1,20 @@
 package tts
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"time"
+	"sync"
 
-	"github.com/gopxl/beep"
-	"github.com/gopxl/beep/speaker"
 	"github.com/sirupsen/logrus"
 )
 
 // Progress 表示播放进度信息
 type Progress struct {
 	CurrentTime float64     // 当前播放时间（秒）
-	TotalTime   float64     // 总时长（秒）
+	TotalTime   float64     // 总时长（秒），Final 为 false 时是目前已知的时长，之后还会增长
+	Final       bool        // 合成已经结束，TotalTime 已经确定
+	Playing     bool        // 是否有还没播放完的 session（暂停时也为 true）
 	CurrentWord *WordTiming // 当前正在播放的词（如果有）
 	PlayedText  string      // 已播放的文本（所有已播放完成的词拼接）
 	Percentage  float64     // 播放进度百分比 (0-100)
@@ -28,51 +30,92 @@ type SayRequest struct {
 	// 未来可以扩展更多参数，如：Speed, Pitch, Volume 等
 }
 
+// SpeakerConfig Speaker 配置
+type SpeakerConfig struct {
+	Buffer BufferConfig // 播放的每个 Streamer 使用的抖动缓冲
+}
+
+// DefaultSpeakerConfig 返回默认配置
+func DefaultSpeakerConfig() SpeakerConfig {
+	return SpeakerConfig{Buffer: DefaultBufferConfig()}
+}
+
 type Speaker struct {
+	cfg         SpeakerConfig
 	tts         Engine
+	sink        AudioSink
 	streamQueue *StreamQueue
+	events      *eventHub
+
+	mu      sync.Mutex
+	session *Streamer // 正在合成的 session，用于记录提交的文本
 }
 
-func NewSpeaker(tts Engine) *Speaker {
+// NewSpeaker 创建 Speaker，音频输出到 sink（本地声卡、文件、网络等，见 tts/sink 包）
+// 输出采样率由 sink 决定，采样率不同的 Streamer（如 24kHz 音色）播放时自动重采样；
+// sink 实现 LatencySink 时播放进度会扣除 sink 中还没有播放出去的部分；cfg 可选，默认 DefaultSpeakerConfig()
+func NewSpeaker(tts Engine, sink AudioSink, cfg ...SpeakerConfig) (*Speaker, error) {
+	if sink == nil {
+		return nil, errors.New("speaker: audio sink is required")
+	}
+
+	c := DefaultSpeakerConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
 	s := &Speaker{
+		cfg:         c,
 		tts:         tts,
+		sink:        sink,
 		streamQueue: NewStreamQueue(),
+		events:      newEventHub(),
+	}
+	s.streamQueue.SetSampleRate(sink.Format().SampleRate)
+	if ls, ok := sink.(LatencySink); ok {
+		s.streamQueue.SetLatency(ls.Latency)
 	}
 
-	// 初始化 speaker
-	// 使用默认采样率，Engine 实现应该在创建 Streamer 时设置正确的采样率
-	sampleRate := beep.SampleRate(16000)
-
-	// 初始化 beep speaker
-	speaker.Init(sampleRate, sampleRate.N(time.Second/10))
-	speaker.Play(s.streamQueue)
+	if err := sink.Play(s.streamQueue); err != nil {
+		return nil, fmt.Errorf("speaker: start sink failed: %w", err)
+	}
 
-	return s
+	return s, nil
 }
 
 // Say 使用 SayRequest 进行语音合成和播放
-func (s *Speaker) Say(req SayRequest) error {
+// ctx 取消时（例如用户打断）正在等待的引擎调用会立即返回
+func (s *Speaker) Say(ctx context.Context, req SayRequest) error {
 	var streamer *Streamer
 	var err error
 
 	if req.Start {
-		streamer, err = s.tts.Start(req.Emotion, req.ContextTexts)
+		streamer, err = s.tts.Start(ctx, req.Emotion, req.ContextTexts)
 		if err != nil {
 			return fmt.Errorf("start session failed: %w", err)
 		}
+		streamer.SetBufferConfig(s.cfg.Buffer)
 		s.streamQueue.Push(streamer)
+		s.events.watch(streamer)
+		s.mu.Lock()
+		s.session = streamer
+		s.mu.Unlock()
 	}
 
 	// 只有当 Text 不为空时才调用 Synthesize
 	if req.Text != "" {
-		err = s.tts.Synthesize(req.Text, req.ContextTexts)
+		err = s.tts.Synthesize(ctx, req.Text, req.ContextTexts)
 		if err != nil {
 			return fmt.Errorf("synthesize failed: %w", err)
 		}
+		s.mu.Lock()
+		if s.session != nil {
+			s.session.AddText(req.Text)
+		}
+		s.mu.Unlock()
 	}
 
 	if req.End {
-		if err := s.tts.End(); err != nil {
+		if err := s.tts.End(ctx); err != nil {
 			logrus.Warnf("speaker: failed to finish session: %v", err)
 		}
 	}
@@ -80,32 +123,61 @@ func (s *Speaker) Say(req SayRequest) error {
 	return nil
 }
 
+// Play 把 streamer 加入播放队列，streamer 的缓冲配置会被设置为 SpeakerConfig.Buffer
 func (s *Speaker) Play(streamer *Streamer) {
+	streamer.SetBufferConfig(s.cfg.Buffer)
 	s.streamQueue.Push(streamer)
+	s.events.watch(streamer)
 }
 
-// func (s *Speaker) Pause() {
-// 	speaker.Lock()
-// 	speaker.Suspend()
-// 	speaker.Unlock()
-// }
+// Subscribe 订阅播放事件（开始、词边界、句子结束、欠载、打断、完成），事件时间基于 sink 的播放时钟
+// buffer 为 channel 容量（<= 0 时为 64），发送不会阻塞播放，channel 满时事件被丢弃；
+// 调用返回的函数取消订阅并关闭 channel，Close 时所有订阅的 channel 也会关闭
+func (s *Speaker) Subscribe(buffer int) (<-chan PlaybackEvent, func()) {
+	return s.events.subscribe(buffer)
+}
+
+// Pause 暂停播放，已缓冲的音频不会丢失，Engine 仍然可以继续写入音频
+func (s *Speaker) Pause() {
+	s.streamQueue.Pause()
+}
 
-// func (s *Speaker) Resume() {
-// 	speaker.Lock()
-// 	speaker.Resume()
-// 	speaker.Unlock()
-// }
+// Resume 从暂停位置继续播放
+func (s *Speaker) Resume() {
+	s.streamQueue.Resume()
+}
+
+// IsPaused 是否处于暂停状态
+func (s *Speaker) IsPaused() bool {
+	return s.streamQueue.Paused()
+}
 
 // 停止播放当前streamer
 func (s *Speaker) Stop() {
-	speaker.Lock()
+	s.Interrupt()
+}
+
+// Interrupt 停止播放（同 Stop），并返回被打断的 session 中用户实际听到的内容
+// 没有正在播放的 session 时第二个返回值为 false
+func (s *Speaker) Interrupt() (Interruption, bool) {
+	in, ok := s.interruption()
 	s.streamQueue.StopCurrent()
-	speaker.Unlock()
+	// 停止后清除暂停状态，保证下次 Say() 能正常播放
+	s.streamQueue.Resume()
 
-	// 结束当前的 TTS session，确保下次 Say() 时能正常开始新 session
-	if err := s.tts.End(); err != nil {
-		logrus.Warnf("speaker: failed to finish session after stop: %v", err)
+	// 取消当前的 TTS session，服务端立即停止合成，确保下次 Say() 时能正常开始新 session
+	if err := s.tts.Cancel(context.Background()); err != nil {
+		logrus.Warnf("speaker: failed to cancel session after stop: %v", err)
 	}
+	return in, ok
+}
+
+// Close 停止当前播放并关闭 sink，不会关闭 Engine
+func (s *Speaker) Close() error {
+	s.streamQueue.StopCurrent()
+	err := s.sink.Close()
+	s.events.close()
+	return err
 }
 
 // GetProgress 获取当前播放进度
@@ -120,6 +192,13 @@ func (s *Speaker) GetProgress() *Progress {
 	progress := &Progress{
 		CurrentTime: currentTime,
 		TotalTime:   totalTime,
+		Final:       currentStreamer.IsFinished(),
+		Playing:     true,
+	}
+	select {
+	case <-currentStreamer.Done():
+		progress.Playing = false
+	default:
 	}
 
 	// 计算百分比
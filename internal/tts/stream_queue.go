@@ -1,18 +1,35 @@
 package tts
 
 import (
+	"ava/internal/audio"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gopxl/beep"
+	"github.com/sirupsen/logrus"
 )
 
 type StreamQueue struct {
-	mu      sync.Mutex
-	current beep.Streamer
-	queue   []beep.Streamer
+	mu         sync.Mutex
+	sampleRate beep.SampleRate // 输出采样率，0 表示不转换
+	current    beep.Streamer
+	output     beep.Streamer // current 转换到 sampleRate 后的结果
+	head       *playhead     // current 的播放位置，current 不是 *Streamer 时为 nil
+	latency    func() time.Duration
+	ended      []*Streamer // 已经取完、等待 sink 播放完缓冲的 Streamer
+	draining   *Streamer   // 最后一个取完但可能还没有播放完的 Streamer
+	queue      []beep.Streamer
+	paused     bool
+}
+
+// formatter 能报告自身格式的 streamer，如 *Streamer、*audio.BeepStreamer
+type formatter interface {
+	Format() beep.Format
 }
 
 // CurrentStreamer 获取当前正在播放的 Streamer（如果是 *Streamer 类型）
+// 数据已经取完、但 sink 中还有没播放完的缓冲时仍然返回它，直到 Done() 关闭
 func (q *StreamQueue) CurrentStreamer() *Streamer {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -20,11 +37,20 @@ func (q *StreamQueue) CurrentStreamer() *Streamer {
 		if s, ok := q.current.(*Streamer); ok {
 			return s
 		}
+		return nil
+	}
+	if s := q.draining; s != nil {
+		select {
+		case <-s.Done():
+		default:
+			return s
+		}
 	}
 	return nil
 }
 
 // StopCurrent 停止当前正在播放的 stream
+// 已经取完、但还在 sink 缓冲中播放的 Streamer 也一起取消，它们的 Done/Err 报告为被打断而不是正常结束
 func (q *StreamQueue) StopCurrent() {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -33,12 +59,83 @@ func (q *StreamQueue) StopCurrent() {
 			s.Cancel()
 		}
 	}
+	for _, s := range q.ended {
+		s.Cancel()
+	}
+	q.ended = q.ended[:0]
+	if s := q.draining; s != nil {
+		select {
+		case <-s.Done():
+		default:
+			s.Cancel()
+		}
+		q.draining = nil
+	}
+}
+
+// Pause 暂停播放：冻结当前 stream，并且不会切换到队列中的下一个 stream
+func (q *StreamQueue) Pause() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.paused = true
+	if s, ok := q.current.(*Streamer); ok {
+		s.Pause()
+	}
+}
+
+// Resume 恢复播放
+func (q *StreamQueue) Resume() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.paused = false
+	if s, ok := q.current.(*Streamer); ok {
+		s.Resume()
+	}
+}
+
+// Paused 是否处于暂停状态
+func (q *StreamQueue) Paused() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.paused
 }
 
 func NewStreamQueue() *StreamQueue {
 	return &StreamQueue{}
 }
 
+// SetSampleRate 设置输出采样率，之后开始播放的 stream 如果实现了 Format() 且采样率不同会自动重采样
+func (q *StreamQueue) SetSampleRate(sr beep.SampleRate) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.sampleRate = sr
+}
+
+// SetLatency 设置输出端的延迟来源（见 LatencySink），之后开始播放的 *Streamer 的进度会扣除这部分
+func (q *StreamQueue) SetLatency(latency func() time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.latency = latency
+}
+
+// adapt 把 s 转换到输出采样率
+func (q *StreamQueue) adapt(s beep.Streamer) beep.Streamer {
+	f, ok := s.(formatter)
+	if !ok || q.sampleRate <= 0 {
+		return s
+	}
+	from := f.Format().SampleRate
+	if from <= 0 || from == q.sampleRate {
+		return s
+	}
+	r, err := audio.Resample(s, from, q.sampleRate)
+	if err != nil {
+		logrus.Warnf("stream queue: resample %d -> %d: %v", from, q.sampleRate, err)
+		return s
+	}
+	return r
+}
+
 func (q *StreamQueue) Push(s beep.Streamer) {
 	q.mu.Lock()
 	q.queue = append(q.queue, s)
@@ -49,18 +146,34 @@ func (q *StreamQueue) Stream(samples [][2]float64) (n int, ok bool) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
+	q.settle()
+	if q.paused {
+		return 0, true
+	}
+
 	for {
 		if q.current == nil {
 			if len(q.queue) == 0 {
 				return 0, true // 暂时无数据，不停止播放
 			}
 			q.current = q.queue[0]
+			q.output = q.adapt(q.current)
+			q.head = q.newPlayhead()
 			q.queue = q.queue[1:]
 		}
 
-		n, ok = q.current.Stream(samples)
+		n, ok = q.output.Stream(samples)
+		if q.head != nil {
+			q.head.samples.Add(int64(n))
+		}
 		if !ok {
+			if s, ok := q.current.(*Streamer); ok {
+				q.ended = append(q.ended, s)
+				q.draining = s
+			}
 			q.current = nil
+			q.output = nil
+			q.head = nil
 			continue
 		}
 		return n, ok
@@ -68,3 +181,49 @@ func (q *StreamQueue) Stream(samples [][2]float64) (n int, ok bool) {
 }
 
 func (q *StreamQueue) Err() error { return nil }
+
+// settle 在 sink 取走上一次 Stream 的数据之后调用：已经取完的 Streamer 在 sink 当前缓冲的音频播放完后标记为完成
+func (q *StreamQueue) settle() {
+	for _, s := range q.ended {
+		var d time.Duration
+		if q.latency != nil {
+			d = q.latency()
+		}
+		if d <= 0 {
+			s.finish()
+		} else {
+			time.AfterFunc(d, s.finish)
+		}
+	}
+	q.ended = q.ended[:0]
+}
+
+// newPlayhead 为刚开始播放的 *Streamer 创建 playhead 并设置为它的播放时钟
+func (q *StreamQueue) newPlayhead() *playhead {
+	s, ok := q.current.(*Streamer)
+	if !ok {
+		return nil
+	}
+	p := &playhead{rate: s.Format().SampleRate, latency: q.latency}
+	if r, ok := q.output.(*audio.Resampler); ok {
+		p.rate = r.SampleRate()
+	}
+	s.setClock(p.position)
+	return p
+}
+
+// playhead 记录一个 stream 输出给 sink 的样本数，扣除 sink 的延迟后即为用户实际听到的位置
+// 与从 Streamer 取出的数据量不同，它不包含重采样滤波器预读的部分
+type playhead struct {
+	rate    beep.SampleRate // 输出采样率
+	samples atomic.Int64    // 已输出的样本数
+	latency func() time.Duration
+}
+
+func (p *playhead) position() time.Duration {
+	d := p.rate.D(int(p.samples.Load()))
+	if p.latency != nil {
+		d -= p.latency()
+	}
+	return max(d, 0)
+}
@@ -1,10 +1,11 @@
 package tts
 
 import (
+	"ava/internal/audio"
 	"bytes"
 	"context"
-	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 	"sync"
 	"time"
@@ -18,8 +19,31 @@ var (
 	ErrEndOfStream   = errors.New("end of stream")
 )
 
+// BufferConfig Streamer 的抖动缓冲配置，用于网络不稳定时避免播放断断续续
+type BufferConfig struct {
+	Prebuffer time.Duration // 开始播放前至少缓冲的音频时长（流提前结束时直接播放），0 表示收到数据立即播放
+	Rebuffer  time.Duration // 播放中缓冲耗尽后，重新缓冲到该时长再继续，0 表示有数据就继续播放
+}
+
+// DefaultBufferConfig 返回 Speaker 默认使用的缓冲配置
+func DefaultBufferConfig() BufferConfig {
+	return BufferConfig{
+		Prebuffer: 100 * time.Millisecond,
+		Rebuffer:  100 * time.Millisecond,
+	}
+}
+
+// StreamerStats 播放统计
+type StreamerStats struct {
+	Underruns int           // 开始播放后缓冲耗尽的次数
+	Rebuffers int           // 缓冲耗尽后重新缓冲的次数
+	Stalled   time.Duration // 开始播放后因缓冲耗尽而停顿的总时长
+}
+
 type Streamer struct {
-	format beep.Format
+	format  beep.Format
+	codec   *audio.Codec  // 缓冲区中音频的编码
+	decoder audio.Decoder // 压缩音频的解码器，AppendAudio 的数据先经过它解码，nil 表示直接写入
 
 	// 使用 bytes.Buffer 作为缓冲区
 	buf *bytes.Buffer
@@ -30,30 +54,66 @@ type Streamer struct {
 	cancel context.CancelFunc
 
 	// 状态管理
-	err           error
-	eos           bool
-	bytesPlayed   int64     // 已播放的字节数
-	startTime     time.Time // 开始播放的时间
-	totalDuration float64   // 总时长（秒），从 TTS 返回的时间信息计算
+	err         error
+	eos         bool                 // 生产者已结束，不会再有新的音频
+	paused      bool                 // 暂停时 Stream 不消费数据，AppendAudio 仍然写入
+	bytesPlayed int64                // 已从缓冲区取出的字节数
+	clock       func() time.Duration // 播放时钟，返回用户实际听到的位置；nil 时按已取出的数据计算
+	done        chan struct{}        // 最后一个样本播放完成（或被取消）后关闭
+	doneOnce    sync.Once
+	starved     bool            // 缓冲已耗尽，等待新的音频
+	underruns   []time.Duration // 每次缓冲耗尽时已取出的音频时长，即播放中断的位置
+	buffering   bool            // 正在（重新）缓冲，缓冲达到阈值或流结束前不输出
+	bufCfg      BufferConfig
+	stalledAt   time.Time // 本次缓冲耗尽的开始时间
+	stats       StreamerStats
 
 	// 时间信息（用于获取已播放文本）
 	timings []SentenceTiming
+	texts   []string // 提交合成的文本片段（保留标点），用于打断时还原用户听到的原文
 }
 
+// NewStreamer 创建接收 16 位小端 PCM 的 Streamer
+// 参数不合法时不会失败：采样率和声道数 <= 0 时使用 audio.DefaultCodecOption，多于 2 个声道按双声道处理
 func NewStreamer(sampleRate beep.SampleRate, channels int) *Streamer {
+	option := audio.CodecOption{
+		Codec:      audio.CodecPCM,
+		SampleRate: int(sampleRate),
+		Channels:   channels,
+		BitDepth:   16,
+	}.WithDefaults()
+	option.Channels = min(option.Channels, 2)
+	// 16 位单/双声道 PCM 总是支持的，校验交给 NewStreamerWithCodec
+	s, _ := NewStreamerWithCodec(option)
+	return s
+}
+
+// NewStreamerWithCodec 创建接收 codec 编码音频的 Streamer，支持 audio.RegisterCodec 注册的所有编码
+func NewStreamerWithCodec(codec audio.CodecOption) (*Streamer, error) {
+	c, err := audio.NewCodec(codec)
+	if err != nil {
+		return nil, fmt.Errorf("tts: streamer: %w", err)
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	s := &Streamer{
 		format: beep.Format{
-			SampleRate:  sampleRate,
-			NumChannels: channels,
+			SampleRate:  beep.SampleRate(c.Option().SampleRate),
+			NumChannels: c.Option().Channels,
 			Precision:   2,
 		},
+		codec:  c,
 		buf:    bytes.NewBuffer(make([]byte, 0, 8192)), // 初始容量 8KB
 		ctx:    ctx,
 		cancel: cancel,
+		done:   make(chan struct{}),
 	}
+	return s, nil
+}
 
-	return s
+// Format 返回解码后的音频格式（采样率和声道数由 Engine 决定，Precision 固定为 PCM16，用于播放端和 WAV 输出）
+// 缓冲区中的原始编码见 CodecOption
+func (s *Streamer) Format() beep.Format {
+	return s.format
 }
 
 func (s *Streamer) AppendAudio(p []byte) {
@@ -68,6 +128,22 @@ func (s *Streamer) AppendAudio(p []byte) {
 		return
 	}
 
+	if s.decoder != nil {
+		if err := s.decoder.Write(p); err != nil {
+			s.mu.Lock()
+			if s.err == nil {
+				s.err = err
+			}
+			s.mu.Unlock()
+			logrus.Errorf("streamer: failed to decode audio: %v", err)
+		}
+		return
+	}
+	s.appendPCM(p)
+}
+
+// appendPCM 把已经是 codec 编码的数据写入缓冲区
+func (s *Streamer) appendPCM(p []byte) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -113,17 +189,44 @@ func (s *Streamer) Stream(samples [][2]float64) (int, bool) {
 		return 0, false
 	}
 
-	bytesPerSample := int(s.format.NumChannels) * int(s.format.Precision)
+	// 暂停时保留缓冲区，不推进播放进度
+	if s.paused {
+		return 0, true
+	}
+
+	if s.buffering {
+		if !s.buffered() {
+			return 0, true
+		}
+		s.buffering = false
+	}
+
+	bytesPerSample := s.codec.Option().BytesPerSample()
 	required := len(samples) * bytesPerSample
 
 	// 检查 buffer 是否有数据（非阻塞）
 	if s.buf.Len() == 0 {
 		if s.eos {
+			if s.clock == nil {
+				// 没有播放时钟时，取完即视为播放完成
+				s.finish()
+			}
 			return 0, false
 		}
 		if s.err != nil {
 			return 0, false
 		}
+		// 开始播放之后缓冲耗尽：合成跟不上播放
+		if s.bytesPlayed > 0 && !s.starved {
+			s.starved = true
+			s.underruns = append(s.underruns, s.playedDuration())
+			s.stalledAt = time.Now()
+			s.stats.Underruns++
+			if s.bufCfg.Rebuffer > 0 {
+				s.buffering = true
+				s.stats.Rebuffers++
+			}
+		}
 		// 没有数据但流还没结束，返回 (0, true) 让 beep 继续轮询
 		// 注意：beep 的 Stream 接口期望非阻塞，所以不能在这里 Wait()
 		return 0, true
@@ -155,39 +258,17 @@ func (s *Streamer) Stream(samples [][2]float64) (int, bool) {
 	}
 
 	// 更新进度
-	if s.bytesPlayed == 0 && s.startTime.IsZero() {
-		s.startTime = time.Now()
-	}
 	s.bytesPlayed += int64(n)
-
-	// 转换到 samples
-	samplesRead := n / bytesPerSample
-	for i := 0; i < samplesRead; i++ {
-		offset := i * bytesPerSample
-
-		if s.format.NumChannels == 1 {
-			v := pcm16ToFloat(chunk[offset:])
-			samples[i][0] = v
-			samples[i][1] = v
-		} else {
-			l := pcm16ToFloat(chunk[offset:])
-			r := pcm16ToFloat(chunk[offset+2:])
-			samples[i][0] = l
-			samples[i][1] = r
-		}
+	if s.starved {
+		s.starved = false
+		s.stats.Stalled += time.Since(s.stalledAt)
 	}
 
+	// 转换到 samples
+	samplesRead := s.codec.Decode(samples, chunk[:n])
 	return samplesRead, true
 }
 
-func pcm16ToFloat(b []byte) float64 {
-	if len(b) < 2 {
-		return 0
-	}
-	v := int16(binary.LittleEndian.Uint16(b))
-	return float64(v) / 32768.0
-}
-
 // min 返回两个整数中的较小值
 func min(a, b int) int {
 	if a < b {
@@ -210,6 +291,12 @@ func (s *Streamer) Err() error {
 // Close 实现 io.Closer 接口，关闭流并设置 EOF 错误
 // 调用 Close() 后，Stream() 方法将返回 (0, false) 表示流结束
 func (s *Streamer) Close() error {
+	// 先等解码器输出剩余的音频
+	if s.decoder != nil {
+		if err := s.decoder.Close(); err != nil {
+			return s.CloseWithError(err)
+		}
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.eos = true
@@ -219,10 +306,33 @@ func (s *Streamer) Close() error {
 	return nil
 }
 
+// CloseWithError 由生产者调用，表示流因错误结束（如服务端合成失败）
+// 已缓冲的音频仍会播放完，之后 Stream() 返回 (0, false)，Err() 返回 err
+func (s *Streamer) CloseWithError(err error) error {
+	if err == nil {
+		return s.Close()
+	}
+	if s.decoder != nil {
+		s.decoder.Close()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.eos = true
+	if s.err == nil || s.err == io.EOF {
+		s.err = err
+	}
+	return nil
+}
+
 // Cancel 取消流，由消费者调用，通知生产者停止写入
-// 调用 Cancel() 后，Stream() 和 AppendAudio() 都会立即停止
+// 调用 Cancel() 后，Stream() 和 AppendAudio() 都会立即停止，Done() 关闭
 func (s *Streamer) Cancel() {
 	s.cancel() // 取消 context，通知生产者
+	s.finish()
+	if s.decoder != nil {
+		// 解码结果已经不需要，不等待
+		go s.decoder.Close()
+	}
 	s.mu.Lock()
 	if s.err == nil {
 		s.err = ErrStreamStopped
@@ -230,20 +340,122 @@ func (s *Streamer) Cancel() {
 	s.mu.Unlock()
 }
 
-// GetProgress 获取播放进度
-func (s *Streamer) GetProgress() (currentTime float64, totalTime float64) {
+// Pause 暂停播放，已缓冲的音频保留，生产者仍然可以继续 AppendAudio
+func (s *Streamer) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused = true
+}
+
+// Resume 从暂停位置继续播放
+func (s *Streamer) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused = false
+}
+
+// IsFinished 生产者是否已经结束（Close 或 CloseWithError），之后不会再有新的音频，Duration 已经确定
+func (s *Streamer) IsFinished() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.eos
+}
+
+// Done 返回在最后一个样本播放完成后关闭的 channel；Cancel 后也会关闭，可以通过 Err() 区分
+// 通过 Speaker 播放时以 sink 实际输出为准（包括 sink 中缓冲的部分），否则以 Stream 取完数据为准
+func (s *Streamer) Done() <-chan struct{} {
+	return s.done
+}
+
+// finish 标记播放完成，可以重复调用
+func (s *Streamer) finish() {
+	s.doneOnce.Do(func() { close(s.done) })
+}
+
+// IsPaused 是否处于暂停状态
+func (s *Streamer) IsPaused() bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	return s.paused
+}
 
-	totalTime = s.totalDuration
+// SetBufferConfig 设置抖动缓冲，NewStreamer 创建的 Streamer 默认不缓冲；
+// 开始播放之后设置时 Prebuffer 不再生效
+func (s *Streamer) SetBufferConfig(cfg BufferConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bufCfg = cfg
+	if s.bytesPlayed == 0 {
+		s.buffering = cfg.Prebuffer > 0
+	}
+}
 
-	if s.startTime.IsZero() {
-		return 0, totalTime
+// buffered 缓冲是否达到继续播放的条件，调用方持有 s.mu
+func (s *Streamer) buffered() bool {
+	if s.eos || s.err != nil {
+		return true
 	}
+	threshold := s.bufCfg.Rebuffer
+	if s.bytesPlayed == 0 {
+		threshold = s.bufCfg.Prebuffer
+	}
+	return s.buf.Len() >= s.format.SampleRate.N(threshold)*s.codec.Option().BytesPerSample()
+}
+
+// Stats 返回播放统计
+func (s *Streamer) Stats() StreamerStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stats := s.stats
+	if s.starved {
+		stats.Stalled += time.Since(s.stalledAt)
+	}
+	return stats
+}
+
+// playedDuration 返回已从缓冲区取出的音频时长
+func (s *Streamer) playedDuration() time.Duration {
+	bytesPerFrame := s.codec.Option().BytesPerSample()
+	if bytesPerFrame == 0 {
+		return 0
+	}
+	return s.format.SampleRate.D(int(s.bytesPlayed) / bytesPerFrame)
+}
+
+// Underruns 返回播放过程中缓冲耗尽（合成跟不上播放）的位置，相对 session 开始的音频
+func (s *Streamer) Underruns() []time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]time.Duration, len(s.underruns))
+	copy(result, s.underruns)
+	return result
+}
+
+// canceled 是否被 Cancel 停止（而不是正常结束或合成失败）
+func (s *Streamer) canceled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ctx.Err() != nil || s.err == ErrStreamStopped
+}
+
+// setClock 设置播放时钟，由 StreamQueue 在开始播放时调用
+func (s *Streamer) setClock(clock func() time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+}
 
-	// 根据已播放的字节数计算当前时间
-	bytesPerSecond := float64(s.format.SampleRate) * float64(s.format.NumChannels) * float64(s.format.Precision)
-	if bytesPerSecond > 0 {
+// GetProgress 获取播放进度
+// 通过 Speaker 播放时按 sink 实际输出的位置计算（扣除 sink 缓冲和输出延迟），否则按已取出的数据计算
+func (s *Streamer) GetProgress() (currentTime float64, totalTime float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	totalTime = s.duration().Seconds()
+
+	if s.clock != nil {
+		currentTime = s.clock().Seconds()
+	} else if bytesPerSecond := float64(s.format.SampleRate) * float64(s.codec.Option().BytesPerSample()); bytesPerSecond > 0 {
 		currentTime = float64(s.bytesPlayed) / bytesPerSecond
 	}
 
@@ -255,13 +467,54 @@ func (s *Streamer) GetProgress() (currentTime float64, totalTime float64) {
 	return currentTime, totalTime
 }
 
+// Duration 返回音频总时长：生产者结束前为目前已知的时长（已收到的音频和最后一个词的结束时间中较长者），
+// 结束后为收到的音频总时长；final 表示时长已经确定（见 IsFinished）
+func (s *Streamer) Duration() (d time.Duration, final bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.duration(), s.eos
+}
+
+func (s *Streamer) duration() time.Duration {
+	received := s.receivedDuration()
+	if s.eos {
+		return received
+	}
+	for i := len(s.timings) - 1; i >= 0; i-- {
+		if words := s.timings[i].Words; len(words) > 0 {
+			end := time.Duration(words[len(words)-1].EndTime * float64(time.Second))
+			return max(received, end)
+		}
+	}
+	return received
+}
+
+// ReceivedDuration 返回已经收到的音频总时长（已播放 + 缓冲中）
+// session 结束后即为整个 session 的音频时长，可用于拼接多个 session 的时间戳
+func (s *Streamer) ReceivedDuration() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.receivedDuration()
+}
+
+func (s *Streamer) receivedDuration() time.Duration {
+	bytesPerFrame := s.codec.Option().BytesPerSample()
+	if bytesPerFrame == 0 {
+		return 0
+	}
+	return s.format.SampleRate.D(int(s.bytesPlayed)/bytesPerFrame + s.buf.Len()/bytesPerFrame)
+}
+
 // ResetProgress 重置播放进度（用于新的播放任务）
 func (s *Streamer) ResetProgress() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.bytesPlayed = 0
-	s.startTime = time.Time{}
-	s.totalDuration = 0
+	s.clock = nil
+	s.starved = false
+	s.underruns = nil
+	s.buffering = s.bufCfg.Prebuffer > 0
+	s.stats = StreamerStats{}
 	s.timings = s.timings[:0] // 清空时间信息
 	// 重置 buffer（保留容量）
 	s.buf.Reset()
@@ -274,6 +527,22 @@ func (s *Streamer) AddTiming(timing SentenceTiming) {
 	s.timings = append(s.timings, timing)
 }
 
+// AddText 记录一段提交给 Engine 合成的文本
+func (s *Streamer) AddText(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.texts = append(s.texts, text)
+}
+
+// GetTexts 获取提交合成的所有文本片段
+func (s *Streamer) GetTexts() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]string, len(s.texts))
+	copy(result, s.texts)
+	return result
+}
+
 // GetTimings 获取所有句子的时间信息
 func (s *Streamer) GetTimings() []SentenceTiming {
 	s.mu.RLock()
@@ -0,0 +1,231 @@
+package tts
+
+import (
+	"ava/internal/audio"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStreamerPauseResume(t *testing.T) {
+	s := NewStreamer(16000, 1)
+	s.AppendAudio(make([]byte, 3200)) // 100ms
+
+	samples := make([][2]float64, 800)
+	if n, ok := s.Stream(samples); n != 800 || !ok {
+		t.Fatalf("unexpected stream result n=%d ok=%v", n, ok)
+	}
+
+	s.Pause()
+	before, _ := s.GetProgress()
+	if n, ok := s.Stream(samples); n != 0 || !ok {
+		t.Fatalf("paused streamer should not consume, n=%d ok=%v", n, ok)
+	}
+	// 暂停期间仍然接收音频
+	s.AppendAudio(make([]byte, 3200))
+	if after, _ := s.GetProgress(); after != before {
+		t.Fatalf("progress advanced while paused, before=%v after=%v", before, after)
+	}
+
+	s.Resume()
+	total := 0
+	for {
+		n, ok := s.Stream(samples)
+		total += n
+		if n == 0 || !ok {
+			break
+		}
+	}
+	if total != 2400 {
+		t.Fatalf("buffered audio lost after resume, got=%d want=%d", total, 2400)
+	}
+}
+
+func TestStreamerFromAudio(t *testing.T) {
+	src := audio.NewPipe(audio.CodecOption{}, 0)
+	s, err := NewStreamerFromAudio(src, src.Codec())
+	if err != nil {
+		t.Fatalf("new streamer: %v", err)
+	}
+	src.Write(audio.Frame{Payload: make([]byte, 640), IsFirst: true})
+	src.Write(audio.Frame{Payload: make([]byte, 640), IsLast: true})
+
+	// Frames 再切回 20ms 的帧
+	frames, err := s.Frames()
+	if err != nil {
+		t.Fatalf("frames: %v", err)
+	}
+	total := 0
+	for {
+		frame, err := frames.Read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("read: %v", err)
+			}
+			break
+		}
+		total += len(frame.Payload)
+	}
+	if total != 1280 {
+		t.Fatalf("unexpected audio size: %d", total)
+	}
+}
+
+func TestStreamQueueProgress(t *testing.T) {
+	q := NewStreamQueue()
+	q.SetSampleRate(16000)
+	q.SetLatency(func() time.Duration { return 50 * time.Millisecond })
+
+	// 24kHz 的 Streamer 重采样到 16kHz 输出
+	s := NewStreamer(24000, 1)
+	s.AppendAudio(make([]byte, 24000*2/5)) // 200ms
+	q.Push(s)
+
+	samples := make([][2]float64, 1600) // 100ms@16kHz
+	if n, _ := q.Stream(samples); n != len(samples) {
+		t.Fatalf("unexpected stream result n=%d", n)
+	}
+	// 进度按 sink 实际输出的位置计算，不包含重采样预读的数据和 sink 中的延迟
+	if current, _ := s.GetProgress(); math.Abs(current-0.05) > 1e-9 {
+		t.Fatalf("unexpected progress: %v", current)
+	}
+}
+
+func TestStreamerDurationAndDone(t *testing.T) {
+	q := NewStreamQueue()
+	q.SetLatency(func() time.Duration { return 20 * time.Millisecond })
+
+	s := NewStreamer(16000, 1)
+	s.AppendAudio(make([]byte, 3200)) // 100ms
+	// 时间戳比已收到的音频长：时长以时间戳为准，但还没有确定
+	s.AddTiming(SentenceTiming{Text: "你好", Words: []WordTiming{{Word: "你", EndTime: 0.1}, {Word: "好", EndTime: 0.2}}})
+	if d, final := s.Duration(); d != 200*time.Millisecond || final {
+		t.Fatalf("unexpected duration before end: %v final=%v", d, final)
+	}
+	s.AppendAudio(make([]byte, 3200))
+	s.Close()
+	if d, final := s.Duration(); d != 200*time.Millisecond || !final || !s.IsFinished() {
+		t.Fatalf("unexpected duration after end: %v final=%v", d, final)
+	}
+
+	q.Push(s)
+	samples := make([][2]float64, 4000)
+	if n, _ := q.Stream(samples); n != 3200 {
+		t.Fatalf("unexpected stream result n=%d", n)
+	}
+	q.Stream(samples)
+	// 数据取完后 sink 中还有缓冲，播放完之前不算结束
+	select {
+	case <-s.Done():
+		t.Fatal("done before sink drained")
+	default:
+	}
+	if q.CurrentStreamer() != s {
+		t.Fatal("draining streamer should still be current")
+	}
+	q.Stream(samples)
+	select {
+	case <-s.Done():
+	case <-time.After(time.Second):
+		t.Fatal("streamer not done after playback")
+	}
+	if q.CurrentStreamer() != nil {
+		t.Fatal("finished streamer should not be current")
+	}
+}
+
+func TestStreamerBuffering(t *testing.T) {
+	s := NewStreamer(16000, 1)
+	s.SetBufferConfig(BufferConfig{Prebuffer: 50 * time.Millisecond, Rebuffer: 50 * time.Millisecond})
+	samples := make([][2]float64, 1600)
+
+	// 缓冲不足 Prebuffer 时不开始播放
+	s.AppendAudio(make([]byte, 640)) // 20ms
+	if n, ok := s.Stream(samples); n != 0 || !ok {
+		t.Fatalf("should wait for prebuffer, n=%d ok=%v", n, ok)
+	}
+	s.AppendAudio(make([]byte, 1280)) // 共 60ms
+	if n, _ := s.Stream(samples); n != 960 {
+		t.Fatalf("unexpected samples after prebuffer: %d", n)
+	}
+
+	// 缓冲耗尽后重新缓冲到 Rebuffer
+	s.Stream(samples)
+	s.AppendAudio(make([]byte, 640))
+	if n, ok := s.Stream(samples); n != 0 || !ok {
+		t.Fatalf("should wait for rebuffer, n=%d ok=%v", n, ok)
+	}
+	// 流结束时不再等待
+	s.Close()
+	if n, _ := s.Stream(samples); n != 320 {
+		t.Fatalf("unexpected samples after end: %d", n)
+	}
+	if n, ok := s.Stream(samples); n != 0 || ok {
+		t.Fatalf("streamer should end, n=%d ok=%v", n, ok)
+	}
+	if stats := s.Stats(); stats.Underruns != 1 || stats.Rebuffers != 1 || stats.Stalled <= 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestNewStreamerInvalidChannels(t *testing.T) {
+	for channels, want := range map[int]int{0: 1, -1: 1, 1: 1, 2: 2, 6: 2} {
+		s := NewStreamer(16000, channels)
+		if got := s.Format().NumChannels; got != want {
+			t.Fatalf("channels %d: got %d, want %d", channels, got, want)
+		}
+	}
+	if s := NewStreamer(0, 1); s.Format().SampleRate <= 0 {
+		t.Fatalf("expected default sample rate, got %d", s.Format().SampleRate)
+	}
+}
+
+func TestStreamQueueStopDraining(t *testing.T) {
+	q := NewStreamQueue()
+	q.SetLatency(func() time.Duration { return time.Second })
+
+	s := NewStreamer(16000, 1)
+	s.AppendAudio(make([]byte, 320))
+	s.Close()
+	q.Push(s)
+
+	samples := make([][2]float64, 512)
+	q.Stream(samples)
+	q.Stream(samples) // 数据已经取完，还在 sink 缓冲中播放
+	if q.CurrentStreamer() != s {
+		t.Fatal("expected draining streamer to be current")
+	}
+
+	q.StopCurrent()
+	select {
+	case <-s.Done():
+	default:
+		t.Fatal("expected Done after StopCurrent")
+	}
+	if !s.canceled() || errors.Is(s.Err(), io.EOF) {
+		t.Fatalf("expected canceled streamer, got err=%v", s.Err())
+	}
+	if q.CurrentStreamer() != nil {
+		t.Fatal("expected no current streamer after StopCurrent")
+	}
+}
+
+func TestResampledStreamerCancel(t *testing.T) {
+	s := NewStreamer(24000, 1)
+	s.AppendAudio(make([]byte, 24000*2/5)) // 200ms
+	r, err := audio.Resample(s, 24000, 16000)
+	if err != nil {
+		t.Fatalf("resample: %v", err)
+	}
+	if n, ok := r.Stream(make([][2]float64, 100)); n != 100 || !ok {
+		t.Fatalf("unexpected stream result n=%d ok=%v", n, ok)
+	}
+
+	// 打断后不再输出重采样器中缓冲的音频
+	s.Cancel()
+	if n, ok := r.Stream(make([][2]float64, 1600)); n != 0 || ok {
+		t.Fatalf("expected (0, false) after cancel, got n=%d ok=%v", n, ok)
+	}
+}
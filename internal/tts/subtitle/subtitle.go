@@ -0,0 +1,209 @@
+// Package subtitle 把 tts.SentenceTiming 导出为 SRT / WebVTT 字幕
+package subtitle
+
+import (
+	"ava/internal/tts"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Options 导出配置
+type Options struct {
+	WordLevel bool          // 逐词（卡拉 OK）字幕：SRT 每个词一条、当前词加下划线；WebVTT 在句子内插入词级时间戳
+	Offset    time.Duration // 所有时间整体偏移
+}
+
+// Cue 一条字幕
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+	Words []tts.WordTiming // 句子内的词，时间已经加上偏移
+}
+
+// Cues 把句子时间戳转换为句子级字幕，没有词时间戳的句子会被跳过
+func Cues(timings []tts.SentenceTiming, opts ...Options) []Cue {
+	opt := resolveOptions(opts)
+	cues := make([]Cue, 0, len(timings))
+	for _, sentence := range timings {
+		if len(sentence.Words) == 0 {
+			continue
+		}
+		sentence = sentence.Shift(opt.Offset.Seconds())
+		text := strings.TrimSpace(sentence.Text)
+		if text == "" {
+			text = joinWords(sentence.Words)
+		}
+		cues = append(cues, Cue{
+			Start: seconds(sentence.Words[0].StartTime),
+			End:   seconds(sentence.Words[len(sentence.Words)-1].EndTime),
+			Text:  text,
+			Words: sentence.Words,
+		})
+	}
+	return cues
+}
+
+// Concat 按播放顺序拼接多个 session 的时间戳，后一个 session 的时间接在前一个 session 的音频之后
+// 适用于同一个 StreamQueue 中依次播放的 Streamer，session 之间的等待不计入时间轴
+func Concat(streamers ...*tts.Streamer) []tts.SentenceTiming {
+	var (
+		result []tts.SentenceTiming
+		offset time.Duration
+	)
+	for _, s := range streamers {
+		for _, timing := range s.GetTimings() {
+			result = append(result, timing.Shift(offset.Seconds()))
+		}
+		offset += s.ReceivedDuration()
+	}
+	return result
+}
+
+// WriteSRT 输出 SRT 字幕
+func WriteSRT(w io.Writer, timings []tts.SentenceTiming, opts ...Options) error {
+	opt := resolveOptions(opts)
+	index := 0
+	for _, cue := range Cues(timings, opt) {
+		if !opt.WordLevel {
+			index++
+			if err := writeSRTCue(w, index, cue.Start, cue.End, cue.Text); err != nil {
+				return err
+			}
+			continue
+		}
+		for i, word := range cue.Words {
+			index++
+			text := highlight(cue.Words, i)
+			if err := writeSRTCue(w, index, seconds(word.StartTime), seconds(word.EndTime), text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteVTT 输出 WebVTT 字幕
+func WriteVTT(w io.Writer, timings []tts.SentenceTiming, opts ...Options) error {
+	opt := resolveOptions(opts)
+	if _, err := io.WriteString(w, "WEBVTT\n\n"); err != nil {
+		return err
+	}
+	for _, cue := range Cues(timings, opt) {
+		text := cue.Text
+		if opt.WordLevel {
+			text = karaoke(cue.Words)
+		}
+		_, err := fmt.Fprintf(w, "%s --> %s\n%s\n\n", formatVTT(cue.Start), formatVTT(cue.End), text)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SRT 返回 SRT 字幕文本
+func SRT(timings []tts.SentenceTiming, opts ...Options) string {
+	var b strings.Builder
+	_ = WriteSRT(&b, timings, opts...)
+	return b.String()
+}
+
+// VTT 返回 WebVTT 字幕文本
+func VTT(timings []tts.SentenceTiming, opts ...Options) string {
+	var b strings.Builder
+	_ = WriteVTT(&b, timings, opts...)
+	return b.String()
+}
+
+func resolveOptions(opts []Options) Options {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return Options{}
+}
+
+func writeSRTCue(w io.Writer, index int, start, end time.Duration, text string) error {
+	_, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n", index, formatSRT(start), formatSRT(end), text)
+	return err
+}
+
+// highlight 返回整句文本，第 current 个词加下划线
+func highlight(words []tts.WordTiming, current int) string {
+	parts := make([]string, len(words))
+	for i, w := range words {
+		parts[i] = w.Word
+		if i == current {
+			parts[i] = "<u>" + w.Word + "</u>"
+		}
+	}
+	return joinParts(words, parts)
+}
+
+// karaoke 在每个词前插入 WebVTT 时间戳，播放器据此逐词高亮
+func karaoke(words []tts.WordTiming) string {
+	parts := make([]string, len(words))
+	for i, w := range words {
+		parts[i] = w.Word
+		if i > 0 {
+			parts[i] = "<" + formatVTT(seconds(w.StartTime)) + ">" + w.Word
+		}
+	}
+	return joinParts(words, parts)
+}
+
+func joinWords(words []tts.WordTiming) string {
+	parts := make([]string, len(words))
+	for i, w := range words {
+		parts[i] = w.Word
+	}
+	return joinParts(words, parts)
+}
+
+// joinParts 拼接词，只在两个拉丁字母/数字结尾和开头的词之间加空格（中文逐字时间戳不加空格）
+func joinParts(words []tts.WordTiming, parts []string) string {
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 && needSpace(words[i-1].Word, words[i].Word) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+func needSpace(prev, next string) bool {
+	last, _ := utf8.DecodeLastRuneInString(prev)
+	first, _ := utf8.DecodeRuneInString(next)
+	return isLatin(last) && isLatin(first)
+}
+
+func isLatin(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond)
+}
+
+// formatSRT 00:00:01,500
+func formatSRT(d time.Duration) string {
+	return formatTimestamp(d, ',')
+}
+
+// formatVTT 00:00:01.500
+func formatVTT(d time.Duration) string {
+	return formatTimestamp(d, '.')
+}
+
+func formatTimestamp(d time.Duration, sep byte) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
@@ -0,0 +1,55 @@
+package subtitle
+
+import (
+	"ava/internal/tts"
+	"testing"
+	"time"
+)
+
+var timings = []tts.SentenceTiming{
+	{Text: "你好。", Words: []tts.WordTiming{
+		{Word: "你", StartTime: 0, EndTime: 0.2},
+		{Word: "好", StartTime: 0.2, EndTime: 0.5},
+	}},
+	{Text: "Hello world", Words: []tts.WordTiming{
+		{Word: "Hello", StartTime: 61.5, EndTime: 62},
+		{Word: "world", StartTime: 62, EndTime: 62.75},
+	}},
+}
+
+func TestSRT(t *testing.T) {
+	want := "1\n00:00:00,000 --> 00:00:00,500\n你好。\n\n" +
+		"2\n00:01:01,500 --> 00:01:02,750\nHello world\n\n"
+	if got := SRT(timings); got != want {
+		t.Fatalf("unexpected srt:\n%s", got)
+	}
+
+	got := SRT(timings[1:], Options{WordLevel: true, Offset: time.Second})
+	want = "1\n00:01:02,500 --> 00:01:03,000\n<u>Hello</u> world\n\n" +
+		"2\n00:01:03,000 --> 00:01:03,750\nHello <u>world</u>\n\n"
+	if got != want {
+		t.Fatalf("unexpected word-level srt:\n%s", got)
+	}
+}
+
+func TestVTT(t *testing.T) {
+	want := "WEBVTT\n\n" +
+		"00:00:00.000 --> 00:00:00.500\n你<00:00:00.200>好\n\n" +
+		"00:01:01.500 --> 00:01:02.750\nHello <00:01:02.000>world\n\n"
+	if got := VTT(timings, Options{WordLevel: true}); got != want {
+		t.Fatalf("unexpected vtt:\n%s", got)
+	}
+}
+
+func TestConcat(t *testing.T) {
+	first := tts.NewStreamer(16000, 1)
+	first.AppendAudio(make([]byte, 16000*2)) // 1 秒
+	first.AddTiming(timings[0])
+	second := tts.NewStreamer(16000, 1)
+	second.AddTiming(timings[0])
+
+	got := Concat(first, second)
+	if len(got) != 2 || got[1].Words[0].StartTime != 1 || got[1].Words[1].EndTime != 1.5 {
+		t.Fatalf("unexpected concat result: %+v", got)
+	}
+}
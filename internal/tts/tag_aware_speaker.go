@@ -1,18 +1,30 @@
 package tts
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"sync"
+)
 
 type TagAwareSpeaker struct {
 	speaker      *Speaker
 	parser       *TagParser
+	segmenter    *Segmenter // 把 <say> 内的 token 片段按句子/子句合并后再合成
 	currentContext []string // 保存当前 say 标签的 context
+
+	mu          sync.Mutex
+	ctx         context.Context // 最近一次 Feed 调用的 ctx，供标签回调和分句器超时输出使用
+	onInterrupt func(Interruption)
 }
 
-func NewTagAwareSpeaker(s *Speaker) *TagAwareSpeaker {
+// NewTagAwareSpeaker 创建 TagAwareSpeaker，可以逐 token 调用 Feed
+// cfg 可选，用于调整 <say> 内文本的分句策略
+func NewTagAwareSpeaker(s *Speaker, cfg ...SegmenterConfig) *TagAwareSpeaker {
 	tas := &TagAwareSpeaker{
 		speaker: s,
 		parser:  NewTagParser(),
 	}
+	tas.segmenter = NewSegmenter(tas.synthesize, cfg...)
 
 	// say 标签
 	tas.parser.RegisterTag("say", TagCallbacks{
@@ -22,11 +34,12 @@ func NewTagAwareSpeaker(s *Speaker) *TagAwareSpeaker {
 			var contextTexts []string
 			if context != "" {
 				contextTexts = []string{context}
-				tas.currentContext = contextTexts // 保存 context 供 OnMiddle 使用
+				tas.setContext(contextTexts) // 保存 context 供分句后的合成使用
 			} else {
-				tas.currentContext = nil
+				tas.setContext(nil)
 			}
-			if err := s.Say(SayRequest{
+			tas.segmenter.Reset()
+			if err := s.Say(tas.context(), SayRequest{
 				Text:         "",
 				Start:        true,
 				End:          false,
@@ -38,19 +51,12 @@ func NewTagAwareSpeaker(s *Speaker) *TagAwareSpeaker {
 			fmt.Println("[say] 开始播放，属性:", attrs)
 		},
 		OnMiddle: func(text string) {
-			// 使用保存的 context
-			if err := s.Say(SayRequest{
-				Text:         text,
-				Start:       false,
-				End:         false,
-				Emotion:     "",
-				ContextTexts: tas.currentContext,
-			}); err != nil {
-				fmt.Println("Say 错误:", err)
-			}
+			// 先缓冲，凑够一句/一个子句再合成
+			tas.segmenter.Write(text)
 		},
 		OnEnd: func() {
-			if err := s.Say(SayRequest{
+			tas.segmenter.Flush()
+			if err := s.Say(tas.context(), SayRequest{
 				Text:         "",
 				Start:       false,
 				End:         true,
@@ -59,42 +65,90 @@ func NewTagAwareSpeaker(s *Speaker) *TagAwareSpeaker {
 			}); err != nil {
 				fmt.Println("结束 Say 错误:", err)
 			}
-			tas.currentContext = nil // 清除 context
+			tas.setContext(nil) // 清除 context
 			fmt.Println("[say] 播放结束")
 		},
 	})
 
 	// pause 标签
-	// tas.parser.RegisterTag("pause", TagCallbacks{
-	// 	OnStart: func(attrs map[string]string) {
-	// 		reason := attrs["reason"]
-	// 		fmt.Println("[pause] 暂停播放, 原因:", reason)
-	// 		s.Pause()
-	// 	},
-	// })
+	tas.parser.RegisterTag("pause", TagCallbacks{
+		OnStart: func(attrs map[string]string) {
+			reason := attrs["reason"]
+			fmt.Println("[pause] 暂停播放, 原因:", reason)
+			s.Pause()
+		},
+	})
 
 	// stop 标签
 	tas.parser.RegisterTag("stop", TagCallbacks{
 		OnStart: func(attrs map[string]string) {
 			reason := attrs["reason"]
 			fmt.Println("[stop] 停止播放, 原因:", reason)
-			s.Stop()
+			tas.segmenter.Reset()
+			if in, ok := s.Interrupt(); ok {
+				tas.mu.Lock()
+				onInterrupt := tas.onInterrupt
+				tas.mu.Unlock()
+				if onInterrupt != nil {
+					onInterrupt(in)
+				}
+			}
 		},
 	})
 
 	// resume 标签
-	// tas.parser.RegisterTag("resume", TagCallbacks{
-	// 	OnStart: func(attrs map[string]string) {
-	// 		reason := attrs["reason"]
-	// 		fmt.Println("[resume] 恢复播放, 原因:", reason)
-	// 		s.Resume()
-	// 	},
-	// })
+	tas.parser.RegisterTag("resume", TagCallbacks{
+		OnStart: func(attrs map[string]string) {
+			reason := attrs["reason"]
+			fmt.Println("[resume] 恢复播放, 原因:", reason)
+			s.Resume()
+		},
+	})
 
 	return tas
 }
 
-// Feed 输入 LLM 输出 XML
-func (tas *TagAwareSpeaker) Feed(xmlChunk string) {
+// Feed 输入 LLM 输出的 XML，可以是完整回复也可以是逐 token 的片段
+// ctx 会传递给标签触发的 Say 调用
+func (tas *TagAwareSpeaker) Feed(ctx context.Context, xmlChunk string) {
+	tas.mu.Lock()
+	tas.ctx = ctx
+	tas.mu.Unlock()
 	tas.parser.Feed(xmlChunk)
 }
+
+// OnInterrupt 设置 <stop> 打断播放后的回调，可用于修正对话历史（见 agent.History）
+func (tas *TagAwareSpeaker) OnInterrupt(fn func(Interruption)) {
+	tas.mu.Lock()
+	defer tas.mu.Unlock()
+	tas.onInterrupt = fn
+}
+
+func (tas *TagAwareSpeaker) context() context.Context {
+	tas.mu.Lock()
+	defer tas.mu.Unlock()
+	if tas.ctx == nil {
+		return context.Background()
+	}
+	return tas.ctx
+}
+
+func (tas *TagAwareSpeaker) setContext(contextTexts []string) {
+	tas.mu.Lock()
+	defer tas.mu.Unlock()
+	tas.currentContext = contextTexts
+}
+
+// synthesize 分句器切出一段文本后调用，可能来自 Feed 也可能来自超时输出
+func (tas *TagAwareSpeaker) synthesize(text string) {
+	tas.mu.Lock()
+	contextTexts := tas.currentContext
+	tas.mu.Unlock()
+
+	if err := tas.speaker.Say(tas.context(), SayRequest{
+		Text:         text,
+		ContextTexts: contextTexts,
+	}); err != nil {
+		fmt.Println("Say 错误:", err)
+	}
+}
@@ -104,10 +104,12 @@ func (p *TagParser) parse() {
 
 		if endIdx == -1 {
 			// 全是正文 → 流式输出
-			if p.activeTag.cb.OnMiddle != nil {
-				p.activeTag.cb.OnMiddle(p.buffer)
+			// 逐 token 输入时结束标签可能被拆成 "<" 和 "/say>"，末尾的 "<" 留到下次再判断
+			text := strings.TrimSuffix(p.buffer, "<")
+			if text != "" && p.activeTag.cb.OnMiddle != nil {
+				p.activeTag.cb.OnMiddle(text)
 			}
-			p.buffer = ""
+			p.buffer = p.buffer[len(text):]
 			return
 		}
 
@@ -0,0 +1,91 @@
+package volc
+
+import (
+	"ava/internal/audio"
+	"ava/internal/tts"
+	"fmt"
+
+	"github.com/gopxl/beep"
+)
+
+// CodecOption 返回 CodecConfig 描述的编码，压缩编码（如 mp3）解码后的 Streamer 编码见 Streamer.CodecOption
+func (c CodecConfig) CodecOption() audio.CodecOption {
+	return audio.CodecOption{
+		Codec:      c.Encoding,
+		SampleRate: c.SampleRate,
+		Channels:   c.Channels,
+		BitDepth:   c.BitDepth,
+	}.WithDefaults()
+}
+
+// outputFormat 根据 CodecConfig 协商出的服务端编码和 Streamer 格式
+//   - PCM 类编码（pcm 各位深、float、pcmu、pcma）：向服务端请求 16 位 PCM，在本地转换
+//   - 压缩编码（mp3、ogg_opus 等）：原样向服务端请求，由 Streamer 用 audio.RegisterDecoder 注册的解码器解码（mp3、ogg_opus 见 decoder.go）
+type outputFormat struct {
+	server    string            // 向服务端请求的编码
+	codec     audio.CodecOption // Streamer 中音频的编码
+	transcode *transcoder       // 服务端 PCM16 到 codec 的转换，不需要时为 nil
+	decode    bool              // 服务端返回压缩音频
+}
+
+// negotiate 校验 CodecConfig 并确定服务端编码
+func negotiate(codec CodecConfig) (outputFormat, error) {
+	option := codec.CodecOption()
+	if _, ok := audio.LookupCodec(option.Codec, option.BitDepth); !ok && audio.HasDecoder(option.Codec) {
+		pcm := option
+		pcm.Codec = audio.CodecPCM
+		pcm.BitDepth = 16
+		return outputFormat{server: option.Codec, codec: pcm, decode: true}, nil
+	}
+
+	t, output, err := newTranscoder(option)
+	if err != nil {
+		if !audio.HasDecoder(option.Codec) {
+			err = fmt.Errorf("%w (compressed encodings need audio.RegisterDecoder)", err)
+		}
+		return outputFormat{}, err
+	}
+	return outputFormat{server: audio.CodecPCM, codec: output, transcode: t}, nil
+}
+
+// newStreamer 为一个 session 创建 Streamer
+func (f outputFormat) newStreamer() (*tts.Streamer, error) {
+	if f.decode {
+		return tts.NewStreamerWithDecoder(f.server, beep.SampleRate(f.codec.SampleRate), f.codec.Channels)
+	}
+	return tts.NewStreamerWithCodec(f.codec)
+}
+
+// transcoder 把服务端返回的 PCM16 转换为 CodecConfig 指定的编码，nil 表示不需要转换
+type transcoder struct {
+	src *audio.Codec
+	dst *audio.Codec
+}
+
+// newTranscoder 校验 option 并返回转换器和 Streamer 使用的编码
+func newTranscoder(option audio.CodecOption) (*transcoder, audio.CodecOption, error) {
+	dst, err := audio.NewCodec(option)
+	if err != nil {
+		return nil, audio.CodecOption{}, fmt.Errorf("volc: codec: %w", err)
+	}
+	option = dst.Option()
+	if option.Codec == audio.CodecPCM && option.BitDepth == 16 {
+		return nil, option, nil
+	}
+
+	server := option
+	server.Codec = audio.CodecPCM
+	server.BitDepth = 16
+	src, err := audio.NewCodec(server)
+	if err != nil {
+		return nil, audio.CodecOption{}, fmt.Errorf("volc: codec: %w", err)
+	}
+	return &transcoder{src: src, dst: dst}, option, nil
+}
+
+func (t *transcoder) convert(p []byte) []byte {
+	if t == nil {
+		return p
+	}
+	return audio.Transcode(p, t.src, t.dst)
+}
@@ -0,0 +1,322 @@
+package volc
+
+import (
+	"ava/pkg/websocket"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	gorilla "github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrConnectionLost   = errors.New("volc: connection lost")
+	ErrConnectionBroken = errors.New("volc: connection broken")
+	ErrEngineClosed     = errors.New("volc: engine closed")
+)
+
+// DefaultEndpoint 火山引擎双向流式 TTS 的服务地址
+const DefaultEndpoint = "wss://openspeech.bytedance.com/api/v3/tts/bidirection"
+
+// ConnState 连接状态
+type ConnState int
+
+const (
+	StateConnecting ConnState = iota // 正在建立连接或重连中
+	StateReady                       // 握手完成，可以启动 session
+	StateBroken                      // 重连次数用尽或未开启重连，需要调用 Reconnect
+	StateClosed                      // 已调用 Close
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateReady:
+		return "ready"
+	case StateBroken:
+		return "broken"
+	case StateClosed:
+		return "closed"
+	default:
+		return fmt.Sprintf("ConnState(%d)", int(s))
+	}
+}
+
+// ReconnectConfig 断线重连配置
+type ReconnectConfig struct {
+	Disabled       bool          // 关闭自动重连，断线后直接进入 StateBroken
+	InitialBackoff time.Duration // 首次重连前的等待时间，默认 500ms
+	MaxBackoff     time.Duration // 指数退避的上限，默认 30s
+	MaxAttempts    int           // 最大连续重连次数，0 表示不限制
+}
+
+// DefaultReconnectConfig 返回默认重连配置
+func DefaultReconnectConfig() ReconnectConfig {
+	return ReconnectConfig{
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+func (c ReconnectConfig) withDefaults() ReconnectConfig {
+	d := DefaultReconnectConfig()
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = d.InitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = d.MaxBackoff
+	}
+	if c.MaxBackoff < c.InitialBackoff {
+		c.MaxBackoff = c.InitialBackoff
+	}
+	return c
+}
+
+// State 返回当前连接状态
+func (e *VolcEngine) State() ConnState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.state
+}
+
+func (e *VolcEngine) setState(state ConnState, err error) {
+	e.mu.Lock()
+	if e.state == state || e.state == StateClosed {
+		e.mu.Unlock()
+		return
+	}
+	e.state = state
+	close(e.stateCh)
+	e.stateCh = make(chan struct{})
+	e.mu.Unlock()
+
+	if err != nil {
+		logrus.Warnf("volc: connection %s: %v", state, err)
+	} else {
+		logrus.Infof("volc: connection %s", state)
+	}
+	if e.onStateChange != nil {
+		e.onStateChange(state, err)
+	}
+}
+
+func (e *VolcEngine) getClient() websocket.WsClient {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.client
+}
+
+// waitReady 等待连接就绪并返回当前连接
+func (e *VolcEngine) waitReady(ctx context.Context) (websocket.WsClient, error) {
+	for {
+		e.mu.Lock()
+		state, ch, client := e.state, e.stateCh, e.client
+		e.mu.Unlock()
+
+		switch state {
+		case StateReady:
+			return client, nil
+		case StateBroken:
+			return nil, ErrConnectionBroken
+		case StateClosed:
+			return nil, ErrEngineClosed
+		}
+
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, errors.New("volc: wait for connection timeout")
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// connect 建立 WebSocket 连接并完成 StartConnection / ConnectionStarted 握手
+func (e *VolcEngine) connect(ctx context.Context) error {
+	header := http.Header{}
+	header.Set("X-Api-App-Key", e.auth.AppKey)
+	header.Set("X-Api-Access-Key", e.auth.AccessKey)
+	header.Set("X-Api-Resource-Id", e.voice.Voice.ResourceID)
+	header.Set("X-Api-Connect-Id", uuid.New().String())
+	header.Set("X-Control-Require-Usage-Tokens-Return", "*")
+
+	config := websocket.WSConfig{
+		URL:     e.endpoint,
+		Headers: header,
+	}
+
+	client, err := websocket.NewWsClient(ctx, config)
+	if err != nil {
+		return fmt.Errorf("volc: dial websocket: %w", HandshakeError(err))
+	}
+
+	e.mu.Lock()
+	e.client = client
+	e.mu.Unlock()
+
+	drain(e.connectionStartedCh)
+	drain(e.failCh)
+	go e.readLoop(client)
+
+	waitCtx, cancel := withDefaultTimeout(ctx, defaultConnectTimeout)
+	defer cancel()
+
+	if err := e.startConnection(waitCtx, client); err != nil {
+		client.Close()
+		return err
+	}
+
+	select {
+	case <-e.connectionStartedCh:
+		logrus.Info("volc: connection started")
+		return nil
+	case err := <-e.failCh:
+		client.Close()
+		return err
+	case <-client.Done():
+		return ErrConnectionLost
+	case <-waitCtx.Done():
+		client.Close()
+		if errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+			return errors.New("volc: start connection timeout")
+		}
+		return waitCtx.Err()
+	}
+}
+
+// startConnection 发送 StartConnection，服务端回复 ConnectionStarted 后才能启动 session
+func (e *VolcEngine) startConnection(ctx context.Context, client websocket.WsClient) error {
+	msg := NewMessageBuilder().
+		WithEventType(EventType_StartConnection).
+		WithPayload([]byte("{}")).
+		Build()
+
+	frame, _ := msg.Marshal()
+	if err := client.Send(ctx, frame); err != nil {
+		return fmt.Errorf("volc: send start connection: %w", err)
+	}
+	return nil
+}
+
+// readLoop 读取一个连接上的消息，连接断开后交给 handleConnectionLost
+func (e *VolcEngine) readLoop(client websocket.WsClient) {
+	for {
+		msg, err := client.Recv(e.ctx)
+		if err != nil {
+			if gorilla.IsCloseError(err, gorilla.CloseNormalClosure) {
+				logrus.Info("volc: normal ws close")
+				err = nil
+			} else if !errors.Is(err, context.Canceled) {
+				logrus.Warnf("volc: ws close error: %v", err)
+			}
+			client.Close()
+			e.handleConnectionLost(client, err)
+			return
+		}
+		e.OnMessage(client, gorilla.BinaryMessage, msg)
+	}
+}
+
+// handleConnectionLost 结束当前 session，并在连接曾经就绪时触发重连
+func (e *VolcEngine) handleConnectionLost(client websocket.WsClient, err error) {
+	e.mu.Lock()
+	// 握手过程中失败由 connect 自己处理；旧连接的退出不影响新连接
+	if e.client != client || e.state != StateReady {
+		e.mu.Unlock()
+		return
+	}
+	if err == nil {
+		err = ErrConnectionLost
+	} else if !errors.Is(err, ErrConnectionLost) {
+		err = fmt.Errorf("%w: %v", ErrConnectionLost, err)
+	}
+	if e.streamer != nil {
+		e.streamer.CloseWithError(err)
+		e.streamer = nil
+	}
+	if e.SessionID != "" {
+		e.SessionID = ""
+		e.sessionErr = err
+	}
+	e.mu.Unlock()
+
+	if e.reconnect.Disabled {
+		e.setState(StateBroken, err)
+		return
+	}
+	e.setState(StateConnecting, err)
+	go e.reconnectLoop()
+}
+
+// reconnectLoop 按指数退避重连，直到成功、次数用尽或引擎关闭
+func (e *VolcEngine) reconnectLoop() {
+	backoff := e.reconnect.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		err := e.connect(e.ctx)
+		if err == nil {
+			e.markReady()
+			return
+		}
+		logrus.Warnf("volc: reconnect attempt %d failed: %v", attempt, err)
+
+		// 鉴权失败重试也不会成功
+		if errors.Is(err, ErrAuth) ||
+			(e.reconnect.MaxAttempts > 0 && attempt >= e.reconnect.MaxAttempts) {
+			e.setState(StateBroken, err)
+			return
+		}
+		backoff *= 2
+		if backoff > e.reconnect.MaxBackoff {
+			backoff = e.reconnect.MaxBackoff
+		}
+	}
+}
+
+// Reconnect 手动重连，用于 StateBroken 之后恢复
+func (e *VolcEngine) Reconnect(ctx context.Context) error {
+	switch e.State() {
+	case StateClosed:
+		return ErrEngineClosed
+	case StateReady:
+		return nil
+	case StateConnecting:
+		// 自动重连进行中，等待其结果
+		_, err := e.waitReady(ctx)
+		return err
+	}
+
+	if old := e.getClient(); old != nil {
+		old.Close()
+	}
+	e.setState(StateConnecting, nil)
+	if err := e.connect(ctx); err != nil {
+		e.setState(StateBroken, err)
+		return err
+	}
+	e.markReady()
+	return nil
+}
+
+// markReady 进入 StateReady；如果连接在握手完成后立刻断开，重新走断线处理
+func (e *VolcEngine) markReady() {
+	e.setState(StateReady, nil)
+	client := e.getClient()
+	select {
+	case <-client.Done():
+		e.handleConnectionLost(client, ErrConnectionLost)
+	default:
+	}
+}
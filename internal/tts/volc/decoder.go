@@ -0,0 +1,55 @@
+package volc
+
+import (
+	"ava/internal/audio"
+	"fmt"
+
+	"github.com/gopxl/beep/mp3"
+	"github.com/pion/opus"
+)
+
+// 注册服务端支持的压缩编码的解码器，CodecConfig.Encoding 为 mp3 / ogg_opus 时使用
+// 使用方可以在自己的 init 中用 audio.RegisterDecoder 替换（如基于 libopus 的实现）
+func init() {
+	audio.RegisterDecoder("mp3", audio.BeepDecoder(mp3.Decode))
+	audio.RegisterDecoder("ogg_opus", audio.OggOpusDecoder(newOpusDecoder))
+}
+
+// opusDecoder 基于 pion/opus（纯 Go）的 Opus 包解码器
+type opusDecoder struct {
+	dec      opus.Decoder
+	channels int
+	buf      []float32
+}
+
+// opusMaxFrame 一个 Opus 包最长 120ms
+const opusMaxFrame = 120
+
+func newOpusDecoder(sampleRate, channels int) (audio.PacketDecoder, error) {
+	dec, err := opus.NewDecoderWithOutput(sampleRate, channels)
+	if err != nil {
+		return nil, fmt.Errorf("volc: opus decoder: %w", err)
+	}
+	return &opusDecoder{
+		dec:      dec,
+		channels: channels,
+		buf:      make([]float32, sampleRate*opusMaxFrame/1000*channels),
+	}, nil
+}
+
+func (d *opusDecoder) DecodePacket(packet []byte) ([][2]float64, error) {
+	n, err := d.dec.DecodeToFloat32(packet, d.buf)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([][2]float64, n)
+	for i := range samples {
+		l := float64(d.buf[i*d.channels])
+		r := l
+		if d.channels > 1 {
+			r = float64(d.buf[i*d.channels+1])
+		}
+		samples[i] = [2]float64{l, r}
+	}
+	return samples, nil
+}
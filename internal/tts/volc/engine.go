@@ -7,20 +7,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/gopxl/beep"
 	gorilla "github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
 )
 
-func init() {
-	//RegisterTTS("tts.volcengine", NewVolcStreamEngine)
-}
-
 // AuthConfig 认证配置
 type AuthConfig struct {
 	AccessKey string
@@ -48,13 +42,44 @@ func NewVoiceConfigByName(voiceName string) (VoiceConfig, error) {
 
 // CodecConfig 编解码配置
 type CodecConfig struct {
-	Encoding   string  // 编码格式，默认 "pcm"
+	Encoding   string  // 编码格式，默认 "pcm"；"pcmu" / "pcma" / "float" 等在本地转换，"mp3" / "ogg_opus" 在本地解码为 PCM
 	SampleRate int     // 采样率，默认 16000
-	BitDepth   int     // 位深度，默认 16
+	BitDepth   int     // 位深度，默认 16；pcm 支持 8/16/24/32，G.711 固定为 8
 	Channels   int     // 声道数，默认 1
 	SpeedRatio float32 // 语速，默认 1.0
 }
 
+const (
+	defaultConnectTimeout = 5 * time.Second  // 等待 ConnectionStarted 的默认超时
+	defaultStartTimeout   = 5 * time.Second  // 等待 SessionStarted 的默认超时
+	defaultFinishTimeout  = 30 * time.Second // 等待 SessionFinished 的默认超时
+	defaultCancelTimeout  = 5 * time.Second  // 等待 SessionCanceled 的默认超时
+)
+
+// withDefaultTimeout 如果调用方没有设置 deadline，使用默认超时
+func withDefaultTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// notify 非阻塞地发送信号，避免调用方已超时返回时阻塞读循环
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// drain 清除上一次遗留的信号
+func drain[T any](ch chan T) {
+	select {
+	case <-ch:
+	default:
+	}
+}
+
 // DefaultCodecConfig 返回默认编解码配置
 func DefaultCodecConfig() CodecConfig {
 	return CodecConfig{
@@ -66,17 +91,36 @@ func DefaultCodecConfig() CodecConfig {
 	}
 }
 
+// Config VolcEngine 的完整配置
+type Config struct {
+	Endpoint  string // 服务地址，默认 DefaultEndpoint，测试时可以指向 volctest.Server
+	Auth      AuthConfig
+	Voice     VoiceConfig
+	Codec     *CodecConfig    // 可选，nil 时使用默认值并应用音色默认值
+	Reconnect ReconnectConfig // 断线重连策略，零值使用 DefaultReconnectConfig()
+
+	// OnStateChange 连接状态变化时回调（可选），err 为导致状态变化的错误
+	OnStateChange func(state ConnState, err error)
+}
+
 type VolcEngine struct {
-	auth  AuthConfig
-	voice VoiceConfig
-	codec CodecConfig
+	endpoint  string
+	auth      AuthConfig
+	voice     VoiceConfig
+	codec     CodecConfig
+	format    outputFormat // 服务端编码和 Streamer 格式，由 codec 协商
+	reconnect ReconnectConfig
 
-	client websocket.WsClient
+	onStateChange func(state ConnState, err error)
 
 	mu       sync.Mutex
-	streamer *tts.Streamer // 单 session streamer
+	client   websocket.WsClient // 当前连接，重连时会被替换，读写需持有 mu
+	streamer *tts.Streamer      // 单 session streamer
+	state    ConnState
+	stateCh  chan struct{} // 状态变化时关闭并替换，用于等待连接就绪
 
-	SessionID string
+	SessionID  string // 当前 session，没有活动 session 时为空，读写需持有 mu
+	sessionErr error  // 上一个 session 失败的原因，下一次 Start 时清除
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -84,6 +128,8 @@ type VolcEngine struct {
 	connectionStartedCh chan struct{}
 	sessionFinishedCh   chan struct{}
 	sessionStartedCh    chan struct{}
+	sessionCanceledCh   chan struct{}
+	failCh              chan error // 服务端错误，交给正在等待的调用
 	recvFirstAudio      bool
 
 	closeOnce sync.Once // 确保只关闭一次
@@ -94,134 +140,83 @@ type VolcEngine struct {
 // NewVolcEngine 创建新的 VolcEngine 并自动建立连接
 // auth 和 voice 是必需参数，codec 可选（如果未提供则使用默认值）
 func NewVolcEngine(ctx context.Context, auth AuthConfig, voice VoiceConfig, codec ...CodecConfig) (*VolcEngine, error) {
+	cfg := Config{Auth: auth, Voice: voice}
+	if len(codec) > 0 {
+		cfg.Codec = &codec[0]
+	}
+	return NewVolcEngineWithConfig(ctx, cfg)
+}
+
+// NewVolcEngineWithConfig 使用完整配置创建 VolcEngine 并建立连接
+// 首次连接失败直接返回错误；连接建立后断线会按 cfg.Reconnect 自动重连
+func NewVolcEngineWithConfig(ctx context.Context, cfg Config) (*VolcEngine, error) {
 	// 验证必需字段
-	if auth.AccessKey == "" {
+	if cfg.Auth.AccessKey == "" {
 		return nil, errors.New("accessKey is required")
 	}
-	if auth.AppKey == "" {
+	if cfg.Auth.AppKey == "" {
 		return nil, errors.New("appKey is required")
 	}
-	if voice.Voice == nil {
+	if cfg.Voice.Voice == nil {
 		return nil, errors.New("voice configuration is required, use NewVoiceConfig() or NewVoiceConfigByName()")
 	}
 
 	// 使用提供的 codec 配置或默认值
 	var codecConfig CodecConfig
-	if len(codec) > 0 {
-		codecConfig = codec[0]
+	if cfg.Codec != nil {
+		codecConfig = *cfg.Codec
 	} else {
 		codecConfig = DefaultCodecConfig()
 		// 如果音色有默认值，应用它们
-		if voice.Voice.DefaultSampleRate > 0 {
-			codecConfig.SampleRate = voice.Voice.DefaultSampleRate
+		if cfg.Voice.Voice.DefaultSampleRate > 0 {
+			codecConfig.SampleRate = cfg.Voice.Voice.DefaultSampleRate
 		}
-		if voice.Voice.DefaultSpeedRatio > 0 {
-			codecConfig.SpeedRatio = voice.Voice.DefaultSpeedRatio
+		if cfg.Voice.Voice.DefaultSpeedRatio > 0 {
+			codecConfig.SpeedRatio = cfg.Voice.Voice.DefaultSpeedRatio
 		}
 	}
 
+	format, err := negotiate(codecConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+
 	e := &VolcEngine{
-		auth:                auth,
-		voice:               voice,
+		endpoint:            endpoint,
+		auth:                cfg.Auth,
+		voice:               cfg.Voice,
 		codec:               codecConfig,
-		connectionStartedCh: make(chan struct{}),
-		sessionFinishedCh:   make(chan struct{}),
-		sessionStartedCh:    make(chan struct{}),
+		format:              format,
+		reconnect:           cfg.Reconnect.withDefaults(),
+		onStateChange:       cfg.OnStateChange,
+		state:               StateConnecting,
+		stateCh:             make(chan struct{}),
+		connectionStartedCh: make(chan struct{}, 1),
+		sessionFinishedCh:   make(chan struct{}, 1),
+		sessionStartedCh:    make(chan struct{}, 1),
+		sessionCanceledCh:   make(chan struct{}, 1),
+		failCh:              make(chan error, 1),
 	}
 
-	// 创建 context
+	// 创建 context，只在 Close 时取消
 	e.ctx, e.cancel = context.WithCancel(ctx)
 
-	// 建立 WebSocket 连接
-	header := http.Header{}
-	header.Set("X-Api-App-Key", auth.AppKey)
-	header.Set("X-Api-Access-Key", auth.AccessKey)
-	header.Set("X-Api-Resource-Id", voice.Voice.ResourceID)
-	header.Set("X-Api-Connect-Id", uuid.New().String())
-	header.Set("X-Control-Require-Usage-Tokens-Return", "*")
-
-	endpoint := "wss://openspeech.bytedance.com/api/v3/tts/bidirection"
-
-	config := websocket.WSConfig{
-		URL:     endpoint,
-		Headers: header,
-	}
-
-	wsClient, err := websocket.NewWsClient(e.ctx, config)
-	if err != nil {
-		e.cancel()
-		return nil, fmt.Errorf("volc: dial websocket: %w", err)
-	}
-
-	e.client = wsClient
-
-	// 启动消息处理循环来模拟原 EventHandler
-	go func() {
-		defer func() {
-			logrus.Info("volc: connection closed")
-			e.mu.Lock()
-			if e.streamer != nil {
-				e.streamer.Close()
-				e.streamer = nil
-			}
-			e.mu.Unlock()
-			if e.cancel != nil {
-				e.cancel()
-			}
-		}()
-
-		for {
-			select {
-			case <-e.ctx.Done():
-				return
-			case <-e.client.Done():
-				return
-			default:
-				msg, err := e.client.Recv(e.ctx)
-				if err != nil {
-					if gorilla.IsCloseError(err, gorilla.CloseNormalClosure) {
-						logrus.Info("volc: normal ws close")
-					} else if err.Error() != "context canceled" {
-						logrus.Warnf("volce: ws close error: %v", err)
-					}
-					return
-				}
-				e.OnMessage(e.client, 1, msg) // 1 = websocket.TextMessage
-			}
-		}
-	}()
-	select {
-	case <-e.connectionStartedCh:
-		logrus.Info("volc: connection started")
-	case <-time.After(5 * time.Second):
+	if err := e.connect(ctx); err != nil {
 		e.cancel()
-		if e.client != nil {
-			e.client.Close()
-		}
-		return nil, errors.New("volc: start connection timeout")
-	case <-e.ctx.Done():
-		if e.client != nil {
-			e.client.Close()
-		}
-		return nil, e.ctx.Err()
+		return nil, err
 	}
+	e.markReady()
 
 	return e, nil
 }
 
 // ------------------------ EventHandler 实现 ------------------------
 
-// OnOpen 实现 EventHandler 接口，连接建立时调用
-func (e *VolcEngine) OnOpen(client interface{}) {
-	msg := NewMessageBuilder().
-		WithEventType(EventType_StartConnection).
-		WithPayload([]byte("{}")).
-		Build()
-
-	frame, _ := msg.Marshal()
-	e.client.Send(context.Background(), frame)
-}
-
 func (e *VolcEngine) OnMessage(c websocket.WsClient, msgType int, msg []byte) {
 	protocolMsg, err := NewMessageFromBytes(msg)
 	if err != nil {
@@ -240,94 +235,203 @@ func (e *VolcEngine) OnError(c websocket.WsClient, err error) {
 	}
 }
 
+// OnClose 连接断开时调用，触发重连
 func (e *VolcEngine) OnClose(c websocket.WsClient) {
-	logrus.Info("volc: connection closed")
-
-	e.mu.Lock()
-	if e.streamer != nil {
-		e.streamer.Close()
-		e.streamer = nil
-	}
-	e.mu.Unlock()
-
-	if e.cancel != nil {
-		e.cancel()
-	}
+	e.handleConnectionLost(c, nil)
 }
 
 func (e *VolcEngine) dispatch(msg *Message) {
 	logrus.Infof("volc: recv message: %s", msg.String())
 	switch {
+	case msg.MsgType == MsgTypeError:
+		err := parseServerError(msg.EventType, msg.ErrorCode, msg.Payload)
+		logrus.Errorf("volc: received error message: %v", err)
+		current := e.isCurrentSession(msg.SessionID)
+		e.failSession(msg.SessionID, err)
+		if current {
+			e.failPending(err)
+		}
+
+	case msg.EventType == EventType_ConnectionFailed:
+		err := parseServerError(msg.EventType, 0, msg.Payload)
+		logrus.Errorf("volc: connection failed: %v", err)
+		e.failPending(err)
+
+	case msg.EventType == EventType_SessionFailed:
+		err := parseServerError(msg.EventType, 0, msg.Payload)
+		logrus.Errorf("volc: session failed: %v", err)
+		current := e.isCurrentSession(msg.SessionID)
+		e.failSession(msg.SessionID, err)
+		if current {
+			e.failPending(err)
+		}
+
 	case msg.MsgType == MsgTypeFullServerResponse &&
 		msg.EventType == EventType_ConnectionStarted:
-		e.connectionStartedCh <- struct{}{}
+		notify(e.connectionStartedCh)
 
 	case msg.EventType == EventType_SessionStarted:
-		e.sessionStartedCh <- struct{}{}
+		e.notifySession(msg, e.sessionStartedCh)
 
 	case msg.MsgType == MsgTypeAudioOnlyServer:
-		e.mu.Lock()
-		streamer := e.streamer
-		e.mu.Unlock()
+		streamer := e.sessionStreamer(msg.SessionID)
 		if streamer != nil {
-			streamer.AppendAudio(msg.Payload)
+			streamer.AppendAudio(e.format.transcode.convert(msg.Payload))
 		}
 
 	case msg.MsgType == MsgTypeFullServerResponse &&
 		msg.EventType == EventType_TTSSentenceEnd:
-		e.handleSentenceEnd(msg.Payload)
+		e.handleSentenceEnd(msg.SessionID, msg.Payload)
 
 	case msg.EventType == EventType_SessionFinished:
-		e.sessionFinishedCh <- struct{}{}
+		e.notifySession(msg, e.sessionFinishedCh)
 
-	case msg.MsgType == MsgTypeError:
-		logrus.Error("volc: received error message: ", msg.String())
+	case msg.EventType == EventType_SessionCanceled:
+		e.notifySession(msg, e.sessionCanceledCh)
+	}
+}
 
+// notifySession 把当前 session 的确认交给正在等待的调用
+// Cancel 超时返回后旧 session 的确认可能在新 session 期间才到达，不能当作新 session 的确认
+func (e *VolcEngine) notifySession(msg *Message, ch chan struct{}) {
+	if !e.isCurrentSession(msg.SessionID) {
+		logrus.Infof("volc: ignore %v of stale session %s", msg.EventType, msg.SessionID)
+		return
 	}
+	notify(ch)
+}
+
+// failPending 把错误交给正在等待服务端响应的调用（Start / End / Cancel / 建连）
+func (e *VolcEngine) failPending(err error) {
+	select {
+	case e.failCh <- err:
+	default:
+	}
+}
+
+// failSession 结束失败的 session，错误通过 streamer.Err() 和后续调用返回
+func (e *VolcEngine) failSession(sessionID string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.SessionID == "" || (sessionID != "" && sessionID != e.SessionID) {
+		return
+	}
+	if e.streamer != nil {
+		e.streamer.CloseWithError(err)
+		e.streamer = nil
+	}
+	e.SessionID = ""
+	e.sessionErr = err
+}
+
+// sessionStreamer 返回 sessionID 对应的 streamer
+// 取消后服务端可能还会发来旧 session 的音频，不能写入新 session 的 streamer
+func (e *VolcEngine) sessionStreamer(sessionID string) *tts.Streamer {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if sessionID != "" && sessionID != e.SessionID {
+		return nil
+	}
+	return e.streamer
+}
+
+// isCurrentSession 判断消息是否属于当前 session，没有 sessionID 的消息（连接级错误）总是属于当前 session
+func (e *VolcEngine) isCurrentSession(sessionID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return sessionID == "" || sessionID == e.SessionID
+}
+
+func (e *VolcEngine) currentSessionID() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.SessionID
 }
 
 // ------------------------ Session Logic ------------------------
 
-func (e *VolcEngine) Start(emotion string, contextTexts []string) (*tts.Streamer, error) {
+// Start 启动 session 并等待 SessionStarted
+// 正在重连时会先等待连接就绪，ctx 没有 deadline 时默认总共等待 5 秒
+func (e *VolcEngine) Start(ctx context.Context, emotion string, contextTexts []string) (*tts.Streamer, error) {
+	ctx, cancel := withDefaultTimeout(ctx, defaultStartTimeout)
+	defer cancel()
+
+	client, err := e.waitReady(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	e.mu.Lock()
 	if e.streamer != nil {
 		e.streamer.Close()
 	}
-	e.streamer = tts.NewStreamer(beep.SampleRate(e.codec.SampleRate), e.codec.Channels)
-	e.mu.Unlock()
-
+	streamer, err := e.format.newStreamer()
+	if err != nil {
+		e.mu.Unlock()
+		return nil, err
+	}
+	e.streamer = streamer
 	e.SessionID = uuid.New().String()
+	e.sessionErr = nil
+	e.mu.Unlock()
 
-	if err := e.startSession(emotion, contextTexts); err != nil {
+	drain(e.sessionStartedCh)
+	drain(e.failCh)
+	if err := e.startSession(ctx, client, emotion, contextTexts); err != nil {
+		e.dropStreamer(streamer)
 		return nil, err
 	}
 
 	select {
 	case <-e.sessionStartedCh:
 		logrus.Info("volc: session started")
-	case <-time.After(5 * time.Second):
-		return nil, errors.New("volc: start session timeout")
-
+	case err := <-e.failCh:
+		e.dropStreamer(streamer)
+		return nil, err
+	case <-client.Done():
+		e.dropStreamer(streamer)
+		return nil, ErrConnectionLost
+	case <-ctx.Done():
+		e.dropStreamer(streamer)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, errors.New("volc: start session timeout")
+		}
+		return nil, ctx.Err()
 	}
 
-	return e.streamer, nil
+	return streamer, nil
+}
+
+// dropStreamer 在 session 启动失败时丢弃对应的 streamer
+func (e *VolcEngine) dropStreamer(streamer *tts.Streamer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.streamer == streamer {
+		e.streamer.Close()
+		e.streamer = nil
+		e.SessionID = ""
+	}
 }
 
 // Close 主动关闭连接并清理资源
 // 实现 Engine 接口，可以安全地多次调用，只会关闭一次
 func (e *VolcEngine) Close() error {
 	e.closeOnce.Do(func() {
-		// 先关闭 streamer
+		// 先进入 StateClosed，避免关闭连接时触发重连
+		e.setState(StateClosed, nil)
+
+		// 关闭 streamer
 		e.mu.Lock()
 		if e.streamer != nil {
 			e.streamer.Close()
 			e.streamer = nil
 		}
+		client := e.client
 		e.mu.Unlock()
 
-		// 关闭 WebSocket 连接（这会触发 OnClose 回调）
-		if e.client != nil {
-			e.client.Close()
+		// 关闭 WebSocket 连接
+		if client != nil {
+			client.Close()
 		}
 
 		// 取消 context
@@ -340,30 +444,124 @@ func (e *VolcEngine) Close() error {
 	return nil
 }
 
-func (e *VolcEngine) End() error {
-	defer func() {
-		e.mu.Lock()
-		if e.streamer != nil {
-			e.streamer.Close()
-			e.streamer = nil
-		}
-		e.mu.Unlock()
-	}()
+// End 结束 session 并等待服务端合成完剩余文本
+// ctx 没有 deadline 时默认等待 30 秒
+func (e *VolcEngine) End(ctx context.Context) error {
+	defer e.endSession()
 
-	e.finishSession()
+	e.mu.Lock()
+	sessionID, client, sessionErr := e.SessionID, e.client, e.sessionErr
+	e.mu.Unlock()
+	if sessionID == "" {
+		// session 已经因为服务端错误或断线结束
+		return sessionErr
+	}
+
+	ctx, cancel := withDefaultTimeout(ctx, defaultFinishTimeout)
+	defer cancel()
+
+	e.drainAcks()
+	if err := e.sendSessionEvent(ctx, client, EventType_FinishSession, sessionID); err != nil {
+		return err
+	}
 
 	select {
 	case <-e.sessionFinishedCh:
 		logrus.Info("volc: session finished")
-	case <-time.After(30 * time.Second):
-		return errors.New("volc: finish session timeout")
+	case <-e.sessionCanceledCh:
+		// 等待期间被 Cancel 打断
+		logrus.Info("volc: session canceled while finishing")
+	case err := <-e.failCh:
+		return err
+	case <-client.Done():
+		return ErrConnectionLost
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return errors.New("volc: finish session timeout")
+		}
+		return ctx.Err()
+	}
+
+	return nil
+}
 
+// drainAcks 清除上一个 session 遗留的确认和错误，例如超时的 Cancel 迟到的 SessionCanceled
+func (e *VolcEngine) drainAcks() {
+	drain(e.sessionFinishedCh)
+	drain(e.sessionCanceledCh)
+	drain(e.failCh)
+}
+
+// Cancel 取消当前 session：服务端立即停止合成，等待 SessionCanceled 后连接可用于下一个 session
+// 没有活动 session 时直接返回，ctx 没有 deadline 时默认等待 5 秒
+func (e *VolcEngine) Cancel(ctx context.Context) error {
+	defer e.endSession()
+
+	e.mu.Lock()
+	sessionID, client := e.SessionID, e.client
+	if e.streamer != nil {
+		// 不再接收这个 session 的音频
+		e.streamer.Close()
+	}
+	e.mu.Unlock()
+	if sessionID == "" {
+		return nil
+	}
+
+	ctx, cancel := withDefaultTimeout(ctx, defaultCancelTimeout)
+	defer cancel()
+
+	e.drainAcks()
+	if err := e.sendSessionEvent(ctx, client, EventType_CancelSession, sessionID); err != nil {
+		return err
+	}
+
+	select {
+	case <-e.sessionCanceledCh:
+		logrus.Info("volc: session canceled")
+	case <-e.sessionFinishedCh:
+		// 服务端在收到取消前已经合成完毕
+		logrus.Info("volc: session finished before cancel")
+	case err := <-e.failCh:
+		// 取消的 session 本身已经失败，连接仍然可用
+		logrus.Infof("volc: session failed while canceling: %v", err)
+	case <-client.Done():
+		// 连接断开，服务端 session 已经不存在
+		logrus.Info("volc: connection lost while canceling")
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return errors.New("volc: cancel session timeout")
+		}
+		return ctx.Err()
 	}
 
 	return nil
 }
 
-func (e *VolcEngine) Synthesize(text string, contextTexts []string) error {
+// endSession 关闭当前 streamer 并清除 session
+func (e *VolcEngine) endSession() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.streamer != nil {
+		e.streamer.Close()
+		e.streamer = nil
+	}
+	e.SessionID = ""
+	e.sessionErr = nil
+}
+
+// Synthesize 发送一段待合成文本，只等待消息进入发送队列
+func (e *VolcEngine) Synthesize(ctx context.Context, text string, contextTexts []string) error {
+	e.mu.Lock()
+	sessionID, client, sessionErr := e.SessionID, e.client, e.sessionErr
+	e.mu.Unlock()
+	if sessionID == "" {
+		if sessionErr != nil {
+			return sessionErr
+		}
+		return tts.ErrNoActiveSession
+	}
+
 	builder := NewRequestBuilder().
 		WithEvent(EventType_TaskRequest).
 		WithText(text)
@@ -377,20 +575,22 @@ func (e *VolcEngine) Synthesize(text string, contextTexts []string) error {
 
 	msg := NewMessageBuilder().
 		WithEventType(EventType_TaskRequest).
-		WithSessionID(e.SessionID).
+		WithSessionID(sessionID).
 		WithPayload(payload).
 		Build()
 
 	frame, _ := msg.Marshal()
-	e.client.Send(context.Background(), frame)
+	if err := client.Send(ctx, frame); err != nil {
+		return fmt.Errorf("volc: send task request: %w", err)
+	}
 
 	logrus.Info("volc: send task request: ", string(payload))
 	return nil
 }
 
-func (e *VolcEngine) startSession(emotion string, contextTexts []string) error {
+func (e *VolcEngine) startSession(ctx context.Context, client websocket.WsClient, emotion string, contextTexts []string) error {
 	audioParams := &AudioParams{
-		Format:          e.codec.Encoding,
+		Format:          e.format.server,
 		SampleRate:      int32(e.codec.SampleRate),
 		EnableTimestamp: true,
 		SpeechRate:      convertSpeechRate(e.codec.SpeedRatio),
@@ -411,28 +611,34 @@ func (e *VolcEngine) startSession(emotion string, contextTexts []string) error {
 
 	msg := NewMessageBuilder().
 		WithEventType(EventType_StartSession).
-		WithSessionID(e.SessionID).
+		WithSessionID(e.currentSessionID()).
 		WithPayload(payload).
 		Build()
 
 	frame, _ := msg.Marshal()
-	e.client.Send(context.Background(), frame)
+	if err := client.Send(ctx, frame); err != nil {
+		return fmt.Errorf("volc: send start session: %w", err)
+	}
 
 	return nil
 }
 
-func (e *VolcEngine) finishSession() {
+// sendSessionEvent 发送 FinishSession / CancelSession 等只带 sessionID 的控制消息
+func (e *VolcEngine) sendSessionEvent(ctx context.Context, client websocket.WsClient, event EventType, sessionID string) error {
 	msg := NewMessageBuilder().
-		WithEventType(EventType_FinishSession).
-		WithSessionID(e.SessionID).
+		WithEventType(event).
+		WithSessionID(sessionID).
 		WithPayload([]byte("{}")).
 		Build()
 
 	frame, _ := msg.Marshal()
-	e.client.Send(context.Background(), frame)
+	if err := client.Send(ctx, frame); err != nil {
+		return fmt.Errorf("volc: send %s: %w", event, err)
+	}
+	return nil
 }
 
-func (e *VolcEngine) handleSentenceEnd(payload []byte) {
+func (e *VolcEngine) handleSentenceEnd(sessionID string, payload []byte) {
 	var timing tts.SentenceTiming
 	if err := json.Unmarshal(payload, &timing); err != nil {
 		logrus.Warnf("volc: failed to parse sentence end timing: %v", err)
@@ -440,11 +646,9 @@ func (e *VolcEngine) handleSentenceEnd(payload []byte) {
 	}
 
 	// 直接添加到 streamer 的时间戳列表
-	e.mu.Lock()
-	if e.streamer != nil {
-		e.streamer.AddTiming(timing)
+	if streamer := e.sessionStreamer(sessionID); streamer != nil {
+		streamer.AddTiming(timing)
 	}
-	e.mu.Unlock()
 
 }
 
@@ -0,0 +1,565 @@
+package volc_test
+
+import (
+	"ava/internal/audio"
+	"ava/internal/tts"
+	"ava/internal/tts/sink"
+	"ava/internal/tts/volc"
+	"ava/internal/tts/volc/volctest"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gopxl/beep/mp3"
+)
+
+func newTestEngine(t *testing.T, srv *volctest.Server, modify ...func(*volc.Config)) *volc.VolcEngine {
+	t.Helper()
+	cfg := volc.Config{
+		Endpoint: srv.URL,
+		Auth:     volc.AuthConfig{AccessKey: "test-access", AppKey: "test-app"},
+		Voice:    volc.NewVoiceConfig(&volc.VoiceProfile{VoiceType: "test_voice", ResourceID: "test-resource"}),
+	}
+	for _, m := range modify {
+		m(&cfg)
+	}
+	engine, err := volc.NewVolcEngineWithConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	t.Cleanup(func() { engine.Close() })
+	return engine
+}
+
+// readAll 读取 streamer 直到结束，返回样本数
+func readAll(t *testing.T, s *tts.Streamer) int {
+	t.Helper()
+	samples := make([][2]float64, 512)
+	total := 0
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		n, ok := s.Stream(samples)
+		total += n
+		if !ok {
+			return total
+		}
+		if n == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	t.Fatal("streamer did not end")
+	return 0
+}
+
+// samplesFor 返回 volctest 为 text 生成的 16kHz 样本数
+func samplesFor(text string) int {
+	return len(volctest.PCM(16000, time.Duration(len([]rune(text)))*volctest.CharDuration)) / 2
+}
+
+func TestEngineSession(t *testing.T) {
+	srv := volctest.NewServer(nil)
+	defer srv.Close()
+	engine := newTestEngine(t, srv)
+
+	if got := srv.Header().Get("X-Api-App-Key"); got != "test-app" {
+		t.Fatalf("unexpected app key header %q", got)
+	}
+
+	ctx := context.Background()
+	streamer, err := engine.Start(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	for _, text := range []string{"你好", "世界"} {
+		if err := engine.Synthesize(ctx, text, nil); err != nil {
+			t.Fatalf("synthesize: %v", err)
+		}
+	}
+	if err := engine.End(ctx); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+
+	if n := readAll(t, streamer); n != samplesFor("你好世界") {
+		t.Fatalf("unexpected samples, got=%d want=%d", n, samplesFor("你好世界"))
+	}
+	timings := streamer.GetTimings()
+	if len(timings) != 2 || timings[1].Text != "世界" {
+		t.Fatalf("unexpected timings: %+v", timings)
+	}
+	if w := timings[1].Words[1]; w.EndTime != (4 * volctest.CharDuration).Seconds() {
+		t.Fatalf("unexpected word timing: %+v", w)
+	}
+}
+
+func TestSpeakerPipeline(t *testing.T) {
+	srv := volctest.NewServer(nil)
+	defer srv.Close()
+	engine := newTestEngine(t, srv)
+
+	out := sink.NewMemorySink()
+	speaker, err := tts.NewSpeaker(engine, out)
+	if err != nil {
+		t.Fatalf("new speaker: %v", err)
+	}
+	defer speaker.Close()
+
+	err = speaker.Say(context.Background(), tts.SayRequest{Text: "离线测试", Start: true, End: true})
+	if err != nil {
+		t.Fatalf("say: %v", err)
+	}
+
+	want := samplesFor("离线测试") * 2
+	deadline := time.Now().Add(2 * time.Second)
+	for len(out.Bytes()) < want && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	got := out.Bytes()
+	if len(got) != want {
+		t.Fatalf("unexpected output size, got=%d want=%d", len(got), want)
+	}
+	if got[0] == 0 && got[1] == 0 {
+		t.Fatal("expected audio from server, got silence")
+	}
+}
+
+func TestEngineSessionFailed(t *testing.T) {
+	srv := volctest.NewServer(func(s *volctest.Session) {
+		s.SendStarted()
+		<-s.Texts()
+		s.SendFailed(45000292, "quota exceeded for types: concurrency")
+	})
+	defer srv.Close()
+	engine := newTestEngine(t, srv)
+
+	ctx := context.Background()
+	streamer, err := engine.Start(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := engine.Synthesize(ctx, "你好", nil); err != nil {
+		t.Fatalf("synthesize: %v", err)
+	}
+
+	readAll(t, streamer)
+	if !errors.Is(streamer.Err(), volc.ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded from streamer, got %v", streamer.Err())
+	}
+	if err := engine.End(ctx); !errors.Is(err, volc.ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded from End, got %v", err)
+	}
+
+	// 连接仍然可用
+	if _, err := engine.Start(ctx, "", nil); err != nil {
+		t.Fatalf("start after failure: %v", err)
+	}
+}
+
+func TestEngineCancel(t *testing.T) {
+	srv := volctest.NewServer(nil)
+	defer srv.Close()
+	engine := newTestEngine(t, srv)
+
+	ctx := context.Background()
+	if _, err := engine.Start(ctx, "", nil); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := engine.Synthesize(ctx, "被打断的句子", nil); err != nil {
+		t.Fatalf("synthesize: %v", err)
+	}
+	if err := engine.Cancel(ctx); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if err := engine.Synthesize(ctx, "x", nil); !errors.Is(err, tts.ErrNoActiveSession) {
+		t.Fatalf("expected ErrNoActiveSession after cancel, got %v", err)
+	}
+
+	streamer, err := engine.Start(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("start after cancel: %v", err)
+	}
+	engine.Synthesize(ctx, "新句子", nil)
+	engine.End(ctx)
+	if n := readAll(t, streamer); n != samplesFor("新句子") {
+		t.Fatalf("unexpected samples after cancel, got=%d want=%d", n, samplesFor("新句子"))
+	}
+}
+
+// lateAckHandler 收到 FinishSession 后等待 finishDelay 才合成全部文本，CancelSession 的确认延迟 cancelDelay 发送
+func lateAckHandler(cancelDelay, finishDelay time.Duration) volctest.Handler {
+	return func(s *volctest.Session) {
+		if err := s.SendStarted(); err != nil {
+			return
+		}
+		var texts []string
+		for {
+			select {
+			case text, ok := <-s.Texts():
+				if !ok {
+					time.Sleep(finishDelay)
+					for _, text := range texts {
+						if err := s.Speak(text); err != nil {
+							return
+						}
+					}
+					_ = s.SendFinished()
+					return
+				}
+				texts = append(texts, text)
+			case <-s.Done():
+				if s.Canceled() {
+					time.Sleep(cancelDelay)
+					_ = s.SendCanceled()
+				}
+				return
+			}
+		}
+	}
+}
+
+func TestEngineEndIgnoresStaleCancelAck(t *testing.T) {
+	srv := volctest.NewServer(lateAckHandler(200*time.Millisecond, 0))
+	defer srv.Close()
+	engine := newTestEngine(t, srv)
+
+	ctx := context.Background()
+	if _, err := engine.Start(ctx, "", nil); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	engine.Synthesize(ctx, "被打断的句子", nil)
+	cancelCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := engine.Cancel(cancelCtx); err == nil {
+		t.Fatal("expected cancel timeout")
+	}
+	// 等迟到的 SessionCanceled 到达，留在 channel 里
+	time.Sleep(400 * time.Millisecond)
+
+	streamer, err := engine.Start(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("start after cancel: %v", err)
+	}
+	engine.Synthesize(ctx, "新句子", nil)
+	if err := engine.End(ctx); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+	if n := readAll(t, streamer); n != samplesFor("新句子") {
+		t.Fatalf("End returned before the session finished, got=%d want=%d", n, samplesFor("新句子"))
+	}
+}
+
+func TestEngineIgnoresOldSessionCancelAck(t *testing.T) {
+	// 旧 session 的 SessionCanceled 在新 session 的 End 等待期间到达
+	srv := volctest.NewServer(lateAckHandler(100*time.Millisecond, 300*time.Millisecond))
+	defer srv.Close()
+	engine := newTestEngine(t, srv)
+
+	ctx := context.Background()
+	if _, err := engine.Start(ctx, "", nil); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	engine.Synthesize(ctx, "被打断的句子", nil)
+	cancelCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := engine.Cancel(cancelCtx); err == nil {
+		t.Fatal("expected cancel timeout")
+	}
+
+	streamer, err := engine.Start(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("start after cancel: %v", err)
+	}
+	engine.Synthesize(ctx, "新句子", nil)
+	if err := engine.End(ctx); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+	if n := readAll(t, streamer); n != samplesFor("新句子") {
+		t.Fatalf("End completed by the old session's ack, got=%d want=%d", n, samplesFor("新句子"))
+	}
+}
+
+func TestEngineReconnect(t *testing.T) {
+	srv := volctest.NewServer(nil)
+	defer srv.Close()
+
+	states := make(chan volc.ConnState, 16)
+	engine := newTestEngine(t, srv, func(cfg *volc.Config) {
+		cfg.Reconnect = volc.ReconnectConfig{InitialBackoff: 10 * time.Millisecond}
+		cfg.OnStateChange = func(state volc.ConnState, err error) { states <- state }
+	})
+
+	ctx := context.Background()
+	streamer, err := engine.Start(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	srv.DropConnections()
+
+	readAll(t, streamer)
+	if !errors.Is(streamer.Err(), volc.ErrConnectionLost) {
+		t.Fatalf("expected ErrConnectionLost from streamer, got %v", streamer.Err())
+	}
+
+	// 等待自动重连完成（跳过首次连接时的 StateReady）
+	reconnecting := false
+	for state := range states {
+		if state == volc.StateConnecting {
+			reconnecting = true
+		}
+		if reconnecting && state == volc.StateReady {
+			break
+		}
+	}
+	if srv.Connections() != 2 {
+		t.Fatalf("expected 2 connections, got %d", srv.Connections())
+	}
+
+	streamer, err = engine.Start(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("start after reconnect: %v", err)
+	}
+	engine.Synthesize(ctx, "重连", nil)
+	if err := engine.End(ctx); err != nil {
+		t.Fatalf("end after reconnect: %v", err)
+	}
+	if n := readAll(t, streamer); n != samplesFor("重连") {
+		t.Fatalf("unexpected samples after reconnect, got=%d want=%d", n, samplesFor("重连"))
+	}
+}
+
+func TestEngineHandshakeRejected(t *testing.T) {
+	srv := volctest.NewServer(nil)
+	defer srv.Close()
+	srv.RejectHandshake(http.StatusUnauthorized, `{"error":"invalid access key"}`)
+
+	_, err := volc.NewVolcEngineWithConfig(context.Background(), volc.Config{
+		Endpoint: srv.URL,
+		Auth:     volc.AuthConfig{AccessKey: "bad", AppKey: "test-app"},
+		Voice:    volc.NewVoiceConfig(&volc.VoiceProfile{VoiceType: "test_voice"}),
+	})
+	if !errors.Is(err, volc.ErrAuth) {
+		t.Fatalf("expected ErrAuth, got %v", err)
+	}
+}
+
+func TestEngineTelephonyCodec(t *testing.T) {
+	srv := volctest.NewServer(nil)
+	defer srv.Close()
+	engine := newTestEngine(t, srv, func(cfg *volc.Config) {
+		cfg.Codec = &volc.CodecConfig{Encoding: "pcmu", SampleRate: 16000, Channels: 1, SpeedRatio: 1}
+	})
+
+	ctx := context.Background()
+	streamer, err := engine.Start(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if codec := streamer.CodecOption(); codec.Codec != "pcmu" || codec.BitDepth != 8 {
+		t.Fatalf("unexpected streamer codec: %+v", codec)
+	}
+	if err := engine.Synthesize(ctx, "电话", nil); err != nil {
+		t.Fatalf("synthesize: %v", err)
+	}
+	if err := engine.End(ctx); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+	// 服务端返回 PCM16，转换为 μ-law 后样本数不变
+	if n := readAll(t, streamer); n != samplesFor("电话") {
+		t.Fatalf("unexpected samples, got=%d want=%d", n, samplesFor("电话"))
+	}
+
+	_, err = volc.NewVolcEngineWithConfig(ctx, volc.Config{
+		Endpoint: srv.URL,
+		Auth:     volc.AuthConfig{AccessKey: "a", AppKey: "b"},
+		Voice:    volc.NewVoiceConfig(&volc.VoiceProfile{VoiceType: "test_voice"}),
+		Codec:    &volc.CodecConfig{Encoding: "pcmu", BitDepth: 16},
+	})
+	if err == nil {
+		t.Fatal("expected error for 16-bit pcmu")
+	}
+}
+
+func TestSpeakerResample(t *testing.T) {
+	srv := volctest.NewServer(nil)
+	defer srv.Close()
+	// 24kHz 音色输出到 16kHz 的 sink
+	engine := newTestEngine(t, srv, func(cfg *volc.Config) {
+		cfg.Codec = &volc.CodecConfig{SampleRate: 24000, Channels: 1, SpeedRatio: 1}
+	})
+
+	out := sink.NewMemorySink()
+	speaker, err := tts.NewSpeaker(engine, out)
+	if err != nil {
+		t.Fatalf("new speaker: %v", err)
+	}
+	defer speaker.Close()
+
+	if err := speaker.Say(context.Background(), tts.SayRequest{Text: "重采样", Start: true, End: true}); err != nil {
+		t.Fatalf("say: %v", err)
+	}
+
+	// 时长不变，样本数按 16kHz 计算
+	want := samplesFor("重采样") * 2
+	deadline := time.Now().Add(2 * time.Second)
+	for len(out.Bytes()) < want && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := len(out.Bytes()); got != want {
+		t.Fatalf("unexpected output size, got=%d want=%d", got, want)
+	}
+}
+
+// compressedHandler 模拟返回压缩音频的服务端：每段文本分块发送一次 data
+func compressedHandler(formats chan<- string, data []byte) volctest.Handler {
+	return func(s *volctest.Session) {
+		formats <- s.Request.ReqParams.AudioParams.Format
+		if err := s.SendStarted(); err != nil {
+			return
+		}
+		for {
+			select {
+			case _, ok := <-s.Texts():
+				if !ok {
+					_ = s.SendFinished()
+					return
+				}
+				for i := 0; i < len(data); i += 500 {
+					if err := s.SendAudio(data[i:min(i+500, len(data))]); err != nil {
+						return
+					}
+				}
+			case <-s.Done():
+				return
+			}
+		}
+	}
+}
+
+// synthesizeCompressed 用 encoding 合成一段文本，返回解码后的样本数
+func synthesizeCompressed(t *testing.T, encoding string, data []byte) int {
+	t.Helper()
+	formats := make(chan string, 1)
+	srv := volctest.NewServer(compressedHandler(formats, data))
+	defer srv.Close()
+	engine := newTestEngine(t, srv, func(cfg *volc.Config) {
+		cfg.Codec = &volc.CodecConfig{Encoding: encoding, SampleRate: 16000, Channels: 1, SpeedRatio: 1}
+	})
+
+	ctx := context.Background()
+	streamer, err := engine.Start(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if codec := streamer.CodecOption(); codec.Codec != audio.CodecPCM || codec.BitDepth != 16 || codec.SampleRate != 16000 {
+		t.Fatalf("unexpected streamer codec: %+v", codec)
+	}
+	if err := engine.Synthesize(ctx, "压缩", nil); err != nil {
+		t.Fatalf("synthesize: %v", err)
+	}
+	if err := engine.End(ctx); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+	n := readAll(t, streamer)
+	if err := streamer.Err(); err != nil && !errors.Is(err, io.EOF) {
+		t.Fatalf("decode: %v", err)
+	}
+	if f := <-formats; f != encoding {
+		t.Fatalf("expected %s requested, got %q", encoding, f)
+	}
+	return n
+}
+
+func TestEngineMP3(t *testing.T) {
+	data, err := os.ReadFile("testdata/tone.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 直接解码得到原始样本数（44.1kHz）
+	s, format, err := mp3.Decode(io.NopCloser(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("decode mp3: %v", err)
+	}
+	total := 0
+	for buf := make([][2]float64, 512); ; {
+		n, ok := s.Stream(buf)
+		total += n
+		if !ok {
+			break
+		}
+	}
+	s.Close()
+	want := total * 16000 / int(format.SampleRate)
+
+	// 重采样到 16kHz 后时长不变
+	if n := synthesizeCompressed(t, "mp3", data); n < want-2 || n > want+2 {
+		t.Fatalf("unexpected samples, got=%d want=%d", n, want)
+	}
+
+	// 没有注册解码器的压缩编码
+	_, err = volc.NewVolcEngineWithConfig(context.Background(), volc.Config{
+		Endpoint: "ws://127.0.0.1:0",
+		Auth:     volc.AuthConfig{AccessKey: "a", AppKey: "b"},
+		Voice:    volc.NewVoiceConfig(&volc.VoiceProfile{VoiceType: "test_voice"}),
+		Codec:    &volc.CodecConfig{Encoding: "aac"},
+	})
+	if err == nil {
+		t.Fatal("expected error for encoding without decoder")
+	}
+}
+
+func TestEngineOggOpus(t *testing.T) {
+	data, err := os.ReadFile("testdata/tiny.ogg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 一个 20ms 的包，去掉 312 个 48kHz 样本的 pre-skip
+	if n, want := synthesizeCompressed(t, "ogg_opus", data), 320-312/3; n != want {
+		t.Fatalf("unexpected samples, got=%d want=%d", n, want)
+	}
+}
+
+func TestEngineReconnectAfterConnectionFailed(t *testing.T) {
+	srv := volctest.NewServer(nil)
+	defer srv.Close()
+
+	states := make(chan volc.ConnState, 16)
+	engine := newTestEngine(t, srv, func(cfg *volc.Config) {
+		cfg.Reconnect = volc.ReconnectConfig{InitialBackoff: 10 * time.Millisecond}
+		cfg.OnStateChange = func(state volc.ConnState, err error) { states <- state }
+	})
+	<-states // 首次连接的 StateReady
+
+	// 重连时服务端暂时返回没有错误码的 ConnectionFailed
+	srv.FailConnection(`{}`)
+	srv.DropConnections()
+	deadline := time.Now().Add(2 * time.Second)
+	for srv.Connections() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if srv.Connections() < 3 {
+		t.Fatalf("expected repeated reconnect attempts, got %d connections", srv.Connections())
+	}
+	srv.FailConnection("")
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case state := <-states:
+			if state == volc.StateBroken {
+				t.Fatal("transient ConnectionFailed should not break the engine")
+			}
+			if state == volc.StateReady {
+				if _, err := engine.Start(context.Background(), "", nil); err != nil {
+					t.Fatalf("start after reconnect: %v", err)
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("engine did not reconnect")
+		}
+	}
+}
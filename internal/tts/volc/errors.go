@@ -0,0 +1,156 @@
+package volc
+
+import (
+	"ava/pkg/websocket"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// 错误类别，可以用 errors.Is(err, volc.ErrQuotaExceeded) 判断
+var (
+	ErrAuth           = errors.New("volc: authentication failed")
+	ErrQuotaExceeded  = errors.New("volc: quota or concurrency exceeded")
+	ErrInvalidSpeaker = errors.New("volc: invalid speaker or resource")
+	ErrTextTooLong    = errors.New("volc: text too long")
+	ErrInvalidRequest = errors.New("volc: invalid request")
+	ErrServer         = errors.New("volc: server error")
+)
+
+// 服务端状态码按前缀分为客户端错误和服务端错误
+const (
+	codeClientErrorMin = 40000000
+	codeClientErrorMax = 49999999
+	codeServerErrorMin = 50000000
+	codeServerErrorMax = 59999999
+)
+
+// ServerError 服务端返回的错误（MsgTypeError、SessionFailed、ConnectionFailed 或握手失败）
+type ServerError struct {
+	Code    uint32    // 服务端错误码，握手失败时为 HTTP 状态码
+	Event   EventType // 失败事件，MsgTypeError 时为 EventType_None
+	Message string    // 服务端返回的错误信息
+	Kind    error     // 错误类别，ErrAuth / ErrQuotaExceeded 等之一
+}
+
+func (e *ServerError) Error() string {
+	if e.Event != EventType_None {
+		return fmt.Sprintf("%v (%s, code %d): %s", e.Kind, e.Event, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%v (code %d): %s", e.Kind, e.Code, e.Message)
+}
+
+// Is 使 errors.Is(err, ErrXxx) 按错误类别匹配
+func (e *ServerError) Is(target error) bool {
+	return e.Kind == target
+}
+
+// errorPayload 兼容服务端错误 payload 中常见的字段
+type errorPayload struct {
+	StatusCode uint32 `json:"status_code"`
+	Code       uint32 `json:"code"`
+	Error      string `json:"error"`
+	Message    string `json:"message"`
+}
+
+// parseServerError 解析错误消息，code 为消息头中的错误码（没有时传 0）
+func parseServerError(event EventType, code uint32, payload []byte) *ServerError {
+	e := &ServerError{
+		Code:    code,
+		Event:   event,
+		Message: strings.TrimSpace(string(payload)),
+	}
+
+	var p errorPayload
+	if err := json.Unmarshal(payload, &p); err == nil {
+		if e.Code == 0 {
+			e.Code = p.StatusCode
+		}
+		if e.Code == 0 {
+			e.Code = p.Code
+		}
+		if p.Error != "" {
+			e.Message = p.Error
+		} else if p.Message != "" {
+			e.Message = p.Message
+		}
+	}
+
+	e.Kind = classify(event, e.Code, e.Message)
+	return e
+}
+
+// ParseServerError 解析 MsgTypeError 消息，供复用同一协议的其它服务（如流式 ASR）使用
+func ParseServerError(code uint32, payload []byte) *ServerError {
+	return parseServerError(EventType_None, code, payload)
+}
+
+// HandshakeError 把 WebSocket 升级失败转换为 ServerError，其它错误原样返回
+func HandshakeError(err error) error {
+	var hs *websocket.HandshakeError
+	if !errors.As(err, &hs) {
+		return err
+	}
+
+	e := &ServerError{
+		Code:    uint32(hs.StatusCode),
+		Message: strings.TrimSpace(hs.Body),
+	}
+	switch hs.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		e.Kind = ErrAuth
+	case http.StatusTooManyRequests:
+		e.Kind = ErrQuotaExceeded
+	default:
+		e.Kind = classify(EventType_None, 0, e.Message)
+		if e.Kind == ErrInvalidRequest && hs.StatusCode >= 500 {
+			e.Kind = ErrServer
+		}
+	}
+	if e.Message == "" {
+		e.Message = hs.Error()
+	}
+	return e
+}
+
+// classify 先按错误信息中的关键字判断具体原因，再按错误码前缀兜底
+// 鉴权失败不可重试，只按明确的鉴权状态码和鉴权相关的短语判断（不匹配 "token"，以免把 token 数超限等误判为鉴权失败）
+func classify(event EventType, code uint32, message string) error {
+	msg := strings.ToLower(message)
+	switch {
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return ErrAuth
+	case containsAny(msg, "unauthorized", "authenticat", "access key", "accesskey", "access token", "app key", "appkey", "appid"):
+		return ErrAuth
+	case containsAny(msg, "quota", "concurrency", "limit exceeded", "rate limit", "too many", "balance", "insufficient"):
+		return ErrQuotaExceeded
+	case containsAny(msg, "speaker", "voice_type", "voice type", "resource", "resourceid"):
+		// 包括 "speaker permission denied"：只是这个音色没有授权，连接凭证仍然有效
+		return ErrInvalidSpeaker
+	case containsAny(msg, "permission denied", "forbidden"):
+		return ErrAuth
+	case containsAny(msg, "too long", "text length", "exceed", "length limit"):
+		return ErrTextTooLong
+	}
+
+	switch {
+	case code >= codeServerErrorMin && code <= codeServerErrorMax:
+		return ErrServer
+	case code >= codeClientErrorMin && code <= codeClientErrorMax:
+		return ErrInvalidRequest
+	default:
+		// 包括没有错误码的 ConnectionFailed：原因不明时按可重试的服务端错误处理
+		return ErrServer
+	}
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package volc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"ava/pkg/websocket"
+)
+
+func TestParseServerError(t *testing.T) {
+	cases := []struct {
+		event   EventType
+		code    uint32
+		payload string
+		want    error
+	}{
+		{EventType_SessionFailed, 0, `{"status_code":45000001,"error":"speaker permission denied"}`, ErrInvalidSpeaker},
+		{EventType_SessionFailed, 0, `{"status_code":45000001,"error":"permission denied"}`, ErrAuth},
+		{EventType_SessionFailed, 0, `{"status_code":45000292,"error":"quota exceeded for types: concurrency"}`, ErrQuotaExceeded},
+		{EventType_SessionFailed, 0, `{"status_code":45000000,"error":"invalid speaker"}`, ErrInvalidSpeaker},
+		{EventType_None, 45000000, `bad request`, ErrInvalidRequest},
+		{EventType_None, 55000000, `internal error`, ErrServer},
+		{EventType_ConnectionFailed, 0, `{}`, ErrServer},
+		{EventType_ConnectionFailed, 0, `{"error":"invalid access token"}`, ErrAuth},
+		{EventType_SessionFailed, 0, `{"status_code":45000000,"error":"token count exceeds limit"}`, ErrTextTooLong},
+		{EventType_None, 0, `{"status_code":401,"error":"bad request"}`, ErrAuth},
+	}
+	for _, c := range cases {
+		err := parseServerError(c.event, c.code, []byte(c.payload))
+		if !errors.Is(err, c.want) {
+			t.Errorf("payload %s: got kind %v, want %v", c.payload, err.Kind, c.want)
+		}
+	}
+
+	err := parseServerError(EventType_SessionFailed, 0, []byte(`{"status_code":45000001,"error":"bad"}`))
+	if err.Code != 45000001 || err.Message != "bad" {
+		t.Fatalf("unexpected parse result: %+v", err)
+	}
+}
+
+func TestHandshakeError(t *testing.T) {
+	hs := &websocket.HandshakeError{StatusCode: http.StatusUnauthorized, Body: "invalid access key"}
+	err := HandshakeError(fmt.Errorf("dial websocket: %w", hs))
+	if !errors.Is(err, ErrAuth) {
+		t.Fatalf("expected ErrAuth, got %v", err)
+	}
+
+	plain := errors.New("connection refused")
+	if got := HandshakeError(plain); got != plain {
+		t.Fatalf("non-handshake error should pass through, got %v", got)
+	}
+}
@@ -0,0 +1,87 @@
+package volc
+
+import (
+	"ava/internal/tts"
+	"context"
+	"errors"
+)
+
+// EngineName 火山引擎双向流式 TTS 的注册名
+const EngineName = "tts.volcengine"
+
+func init() {
+	tts.Register(EngineName, NewVolcEngineFromConfig, tts.EngineInfo{
+		Version:      "v3",
+		Description:  "火山引擎双向流式语音合成（WebSocket）",
+		Capabilities: []string{"streaming", "timestamp", "emotion", "context_texts"},
+		Config: map[string]string{
+			"endpoint":   "服务地址，默认 " + DefaultEndpoint,
+			"accessKey":  "X-Api-Access-Key（必需）",
+			"appKey":     "X-Api-App-Key（必需）",
+			"voice":      "音色库中的名称，如 meilin_nvyou，与 voiceType 二选一",
+			"voiceType":  "音色 ID，如 zh_female_meilinvyou_saturn_bigtts",
+			"resourceId": "资源 ID，配合 voiceType 使用，默认 seed-tts-2.0",
+			"encoding":   "编码格式，默认 pcm",
+			"sampleRate": "采样率，默认 16000 或音色默认值",
+			"bitDepth":   "位深度，默认 16",
+			"channels":   "声道数，默认 1",
+			"speedRatio": "语速，默认 1.0 或音色默认值",
+		},
+	})
+}
+
+// NewVolcEngineFromConfig 根据通用配置创建 VolcEngine，用于 tts.New
+func NewVolcEngineFromConfig(ctx context.Context, config tts.EngineConfig) (tts.Engine, error) {
+	auth := AuthConfig{
+		AccessKey: config.String("accessKey", ""),
+		AppKey:    config.String("appKey", ""),
+	}
+
+	var voice VoiceConfig
+	if name := config.String("voice", ""); name != "" {
+		v, err := NewVoiceConfigByName(name)
+		if err != nil {
+			return nil, err
+		}
+		voice = v
+	} else if voiceType := config.String("voiceType", ""); voiceType != "" {
+		voice = NewVoiceConfig(&VoiceProfile{
+			VoiceType:  voiceType,
+			ResourceID: config.String("resourceId", "seed-tts-2.0"),
+		})
+	} else {
+		return nil, errors.New("volc: either voice or voiceType is required")
+	}
+
+	codec := DefaultCodecConfig()
+	if voice.Voice.DefaultSampleRate > 0 {
+		codec.SampleRate = voice.Voice.DefaultSampleRate
+	}
+	if voice.Voice.DefaultSpeedRatio > 0 {
+		codec.SpeedRatio = voice.Voice.DefaultSpeedRatio
+	}
+
+	codec.Encoding = config.String("encoding", codec.Encoding)
+	var err error
+	if codec.SampleRate, err = config.Int("sampleRate", codec.SampleRate); err != nil {
+		return nil, err
+	}
+	if codec.BitDepth, err = config.Int("bitDepth", codec.BitDepth); err != nil {
+		return nil, err
+	}
+	if codec.Channels, err = config.Int("channels", codec.Channels); err != nil {
+		return nil, err
+	}
+	speed, err := config.Float("speedRatio", float64(codec.SpeedRatio))
+	if err != nil {
+		return nil, err
+	}
+	codec.SpeedRatio = float32(speed)
+
+	return NewVolcEngineWithConfig(ctx, Config{
+		Endpoint: config.String("endpoint", ""),
+		Auth:     auth,
+		Voice:    voice,
+		Codec:    &codec,
+	})
+}
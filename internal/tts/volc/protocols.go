@@ -381,7 +381,7 @@ func (m *Message) Unmarshal(data []byte) error {
 		return err
 	}
 
-	m.Serialization = SerializationBits(serializationCompression & 0b11110000)
+	m.Serialization = SerializationBits(serializationCompression >> 4)
 	m.Compression = CompressionBits(serializationCompression & 0b00001111)
 
 	headerSize := 4 * int(m.HeaderSize)
@@ -412,7 +412,7 @@ func (m *Message) Unmarshal(data []byte) error {
 
 func (m *Message) writers() (writers []func(*bytes.Buffer) error, _ error) {
 	if m.MsgTypeFlag == MsgTypeFlagWithEvent {
-		writers = append(writers, m.writeEvent, m.writeSessionID)
+		writers = append(writers, m.writeEvent, m.writeSessionID, m.writeConnectID)
 	}
 
 	switch m.MsgType {
@@ -437,7 +437,8 @@ func (m *Message) writeEvent(buf *bytes.Buffer) error {
 func (m *Message) writeSessionID(buf *bytes.Buffer) error {
 	switch m.EventType {
 	case EventType_StartConnection, EventType_FinishConnection,
-		EventType_ConnectionStarted, EventType_ConnectionFailed:
+		EventType_ConnectionStarted, EventType_ConnectionFailed,
+		EventType_ConnectionFinished:
 		return nil
 	}
 
@@ -454,6 +455,27 @@ func (m *Message) writeSessionID(buf *bytes.Buffer) error {
 	return nil
 }
 
+func (m *Message) writeConnectID(buf *bytes.Buffer) error {
+	switch m.EventType {
+	case EventType_ConnectionStarted, EventType_ConnectionFailed,
+		EventType_ConnectionFinished:
+	default:
+		return nil
+	}
+
+	size := len(m.ConnectID)
+	if size > math.MaxUint32 {
+		return fmt.Errorf("connect ID size (%d) exceeds max(uint32)", size)
+	}
+
+	if err := binary.Write(buf, binary.BigEndian, uint32(size)); err != nil {
+		return err
+	}
+
+	buf.WriteString(m.ConnectID)
+	return nil
+}
+
 func (m *Message) writeSequence(buf *bytes.Buffer) error {
 	return binary.Write(buf, binary.BigEndian, m.Sequence)
 }
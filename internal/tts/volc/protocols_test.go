@@ -0,0 +1,26 @@
+package volc
+
+import "testing"
+
+func TestMessageSerializationRoundTrip(t *testing.T) {
+	msg := NewMessageBuilder().
+		WithMsgType(MsgTypeFullClientRequest).
+		WithEventType(EventType_TaskRequest).
+		WithSessionID("session").
+		WithPayload([]byte("{}")).
+		Build()
+	msg.Serialization = SerializationJSON
+
+	data, err := msg.Marshal()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	got, err := NewMessageFromBytes(data)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	// 序列化方式在第 3 个字节的高 4 位
+	if got.Serialization != SerializationJSON || got.Compression != msg.Compression {
+		t.Fatalf("unexpected serialization/compression: %d/%d", got.Serialization, got.Compression)
+	}
+}
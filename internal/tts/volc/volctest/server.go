@@ -0,0 +1,241 @@
+// Package volctest 提供本地的火山引擎双向流式 TTS 服务端，用于离线测试 volc 引擎和 Speaker
+//
+// 服务端使用 volc.Message 编解码，自动完成 StartConnection / ConnectionStarted 握手，
+// 每个 session 交给 Handler 按脚本回复音频、时间戳、错误或断开连接。
+package volctest
+
+import (
+	"ava/internal/tts/volc"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// Handler 处理一个 session，在独立的 goroutine 中运行
+type Handler func(s *Session)
+
+// Server 本地假服务端，URL 可以直接作为 volc.Config.Endpoint
+type Server struct {
+	URL string // ws://127.0.0.1:port
+
+	srv      *httptest.Server
+	handler  Handler
+	upgrader gorilla.Upgrader
+
+	mu          sync.Mutex
+	conns       map[*conn]struct{}
+	connections int         // 累计建立的连接数
+	header      http.Header // 最近一次握手的请求头
+	rejectCode  int         // 非 0 时以该 HTTP 状态码拒绝握手
+	rejectBody  string
+	failConnect string // 非空时用该 payload 回复 ConnectionFailed
+}
+
+// NewServer 启动假服务端，handler 为 nil 时使用 DefaultHandler
+func NewServer(handler Handler) *Server {
+	if handler == nil {
+		handler = DefaultHandler
+	}
+	s := &Server{
+		handler: handler,
+		conns:   map[*conn]struct{}{},
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = "ws" + strings.TrimPrefix(s.srv.URL, "http")
+	return s
+}
+
+// Close 断开所有连接并关闭服务端
+func (s *Server) Close() {
+	s.DropConnections()
+	s.srv.Close()
+}
+
+// Connections 返回累计建立的连接数，用于检查重连
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+// Header 返回最近一次握手的请求头
+func (s *Server) Header() http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.header.Clone()
+}
+
+// RejectHandshake 之后的握手返回 status 和 body，status 为 0 时恢复正常
+func (s *Server) RejectHandshake(status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejectCode, s.rejectBody = status, body
+}
+
+// FailConnection 之后的 StartConnection 回复 ConnectionFailed，payload 为空时恢复正常
+func (s *Server) FailConnection(payload string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failConnect = payload
+}
+
+// DropConnections 直接断开所有连接，模拟网络中断
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	conns := make([]*conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		c.ws.Close()
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.header = r.Header.Clone()
+	code, body := s.rejectCode, s.rejectBody
+	s.mu.Unlock()
+
+	if code != 0 {
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(body))
+		return
+	}
+
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logrus.Warnf("volctest: upgrade failed: %v", err)
+		return
+	}
+
+	c := &conn{
+		server:    s,
+		ws:        ws,
+		connectID: r.Header.Get("X-Api-Connect-Id"),
+		sessions:  map[string]*Session{},
+	}
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	s.connections++
+	s.mu.Unlock()
+
+	c.serve()
+
+	s.mu.Lock()
+	delete(s.conns, c)
+	s.mu.Unlock()
+}
+
+// conn 一个客户端连接
+type conn struct {
+	server    *Server
+	ws        *gorilla.Conn
+	connectID string
+
+	writeMu  sync.Mutex
+	sessions map[string]*Session // 只在 serve 中访问
+}
+
+func (c *conn) send(msg *volc.Message) error {
+	frame, err := msg.Marshal()
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.ws.WriteMessage(gorilla.BinaryMessage, frame)
+}
+
+func (c *conn) serve() {
+	defer func() {
+		c.ws.Close()
+		for _, sess := range c.sessions {
+			sess.stop()
+		}
+	}()
+
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		msg, err := volc.NewMessageFromBytes(data)
+		if err != nil {
+			logrus.Warnf("volctest: invalid message: %v", err)
+			return
+		}
+		if !c.dispatch(msg) {
+			return
+		}
+	}
+}
+
+// dispatch 处理一条客户端消息，返回 false 表示关闭连接
+func (c *conn) dispatch(msg *volc.Message) bool {
+	switch msg.EventType {
+	case volc.EventType_StartConnection:
+		c.server.mu.Lock()
+		failure := c.server.failConnect
+		c.server.mu.Unlock()
+
+		if failure != "" {
+			c.sendConnectionEvent(volc.EventType_ConnectionFailed, failure)
+			return false
+		}
+		c.sendConnectionEvent(volc.EventType_ConnectionStarted, "{}")
+
+	case volc.EventType_FinishConnection:
+		c.sendConnectionEvent(volc.EventType_ConnectionFinished, "{}")
+		return false
+
+	case volc.EventType_StartSession:
+		sess := newSession(c, msg.SessionID)
+		_ = json.Unmarshal(msg.Payload, &sess.Request)
+		c.sessions[msg.SessionID] = sess
+		go c.server.handler(sess)
+
+	case volc.EventType_TaskRequest:
+		sess, ok := c.sessions[msg.SessionID]
+		if !ok {
+			return true
+		}
+		var req volc.Request
+		if err := json.Unmarshal(msg.Payload, &req); err != nil || req.ReqParams == nil {
+			return true
+		}
+		sess.push(req.ReqParams.Text)
+
+	case volc.EventType_FinishSession:
+		if sess, ok := c.sessions[msg.SessionID]; ok {
+			sess.finish()
+			delete(c.sessions, msg.SessionID)
+		}
+
+	case volc.EventType_CancelSession:
+		if sess, ok := c.sessions[msg.SessionID]; ok {
+			sess.cancel()
+			delete(c.sessions, msg.SessionID)
+		}
+	}
+	return true
+}
+
+func (c *conn) sendConnectionEvent(event volc.EventType, payload string) {
+	msg := volc.NewMessageBuilder().
+		WithMsgType(volc.MsgTypeFullServerResponse).
+		WithEventType(event).
+		WithPayload([]byte(payload)).
+		Build()
+	msg.ConnectID = c.connectID
+	if err := c.send(msg); err != nil {
+		logrus.Warnf("volctest: send %s: %v", event, err)
+	}
+}
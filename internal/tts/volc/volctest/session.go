@@ -0,0 +1,211 @@
+package volctest
+
+import (
+	"ava/internal/tts"
+	"ava/internal/tts/volc"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CharDuration Speak 为每个字符生成的音频时长
+const CharDuration = 20 * time.Millisecond
+
+// SampleValue Speak 生成的 PCM 样本值，测试可以据此确认音频经过了整条链路
+const SampleValue int16 = 1000
+
+// Session 服务端的一个 session
+type Session struct {
+	ID      string
+	Request volc.Request // StartSession 请求，包含音色和音频参数
+
+	conn     *conn
+	texts    chan string
+	done     chan struct{}
+	doneOnce sync.Once
+	canceled atomic.Bool
+	elapsed  time.Duration // 已生成音频的时长，用于计算时间戳
+}
+
+func newSession(c *conn, id string) *Session {
+	return &Session{
+		ID:    id,
+		conn:  c,
+		texts: make(chan string, 64),
+		done:  make(chan struct{}),
+	}
+}
+
+// Texts 按顺序返回 TaskRequest 中的文本，收到 FinishSession 后关闭
+func (s *Session) Texts() <-chan string {
+	return s.texts
+}
+
+// Done 收到 CancelSession 或连接断开时关闭
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Canceled 是否收到了 CancelSession
+func (s *Session) Canceled() bool {
+	return s.canceled.Load()
+}
+
+// SampleRate 客户端请求的采样率，未指定时为 16000
+func (s *Session) SampleRate() int {
+	if p := s.Request.ReqParams; p != nil && p.AudioParams != nil && p.AudioParams.SampleRate > 0 {
+		return int(p.AudioParams.SampleRate)
+	}
+	return 16000
+}
+
+func (s *Session) push(text string) {
+	select {
+	case s.texts <- text:
+	default:
+		logrus.Warnf("volctest: session %s text queue full, drop %q", s.ID, text)
+	}
+}
+
+func (s *Session) finish() {
+	close(s.texts)
+}
+
+func (s *Session) cancel() {
+	s.canceled.Store(true)
+	s.stop()
+}
+
+func (s *Session) stop() {
+	s.doneOnce.Do(func() { close(s.done) })
+}
+
+// SendStarted 回复 SessionStarted
+func (s *Session) SendStarted() error {
+	return s.sendEvent(volc.EventType_SessionStarted, []byte("{}"))
+}
+
+// SendAudio 发送一帧 PCM 音频
+func (s *Session) SendAudio(pcm []byte) error {
+	msg := volc.NewMessageBuilder().
+		WithMsgType(volc.MsgTypeAudioOnlyServer).
+		WithEventType(volc.EventType_TTSResponse).
+		WithSessionID(s.ID).
+		WithPayload(pcm).
+		Build()
+	return s.conn.send(msg)
+}
+
+// SendSentenceEnd 发送 TTSSentenceEnd 和句子时间戳
+func (s *Session) SendSentenceEnd(timing tts.SentenceTiming) error {
+	payload, err := json.Marshal(timing)
+	if err != nil {
+		return err
+	}
+	return s.sendEvent(volc.EventType_TTSSentenceEnd, payload)
+}
+
+// SendFinished 回复 SessionFinished
+func (s *Session) SendFinished() error {
+	return s.sendEvent(volc.EventType_SessionFinished, []byte(`{"status_code":20000000,"message":"ok"}`))
+}
+
+// SendCanceled 回复 SessionCanceled
+func (s *Session) SendCanceled() error {
+	return s.sendEvent(volc.EventType_SessionCanceled, []byte("{}"))
+}
+
+// SendFailed 回复 SessionFailed
+func (s *Session) SendFailed(code uint32, message string) error {
+	payload, _ := json.Marshal(map[string]any{"status_code": code, "message": message})
+	return s.sendEvent(volc.EventType_SessionFailed, payload)
+}
+
+// SendError 发送 MsgTypeError 错误帧
+func (s *Session) SendError(code uint32, message string) error {
+	payload, _ := json.Marshal(map[string]any{"error": message})
+	msg := volc.NewMessageBuilder().
+		WithMsgType(volc.MsgTypeError).
+		WithFlag(volc.MsgTypeFlagNoSeq).
+		WithPayload(payload).
+		Build()
+	msg.ErrorCode = code
+	return s.conn.send(msg)
+}
+
+// Disconnect 直接断开 session 所在的连接，不发送任何事件
+func (s *Session) Disconnect() {
+	s.conn.ws.Close()
+}
+
+// Speak 为 text 生成音频（每个字符 CharDuration）并发送对应的 TTSSentenceEnd
+// 时间戳从 session 开始累计，与真实服务端一致
+func (s *Session) Speak(text string) error {
+	words := []rune(text)
+	timing := tts.SentenceTiming{Text: text}
+	start := s.elapsed
+	for i, r := range words {
+		timing.Words = append(timing.Words, tts.WordTiming{
+			Word:       string(r),
+			StartTime:  (start + time.Duration(i)*CharDuration).Seconds(),
+			EndTime:    (start + time.Duration(i+1)*CharDuration).Seconds(),
+			Confidence: 1,
+		})
+	}
+
+	d := time.Duration(len(words)) * CharDuration
+	s.elapsed += d
+	if err := s.SendAudio(PCM(s.SampleRate(), d)); err != nil {
+		return err
+	}
+	return s.SendSentenceEnd(timing)
+}
+
+func (s *Session) sendEvent(event volc.EventType, payload []byte) error {
+	msg := volc.NewMessageBuilder().
+		WithMsgType(volc.MsgTypeFullServerResponse).
+		WithEventType(event).
+		WithSessionID(s.ID).
+		WithPayload(payload).
+		Build()
+	return s.conn.send(msg)
+}
+
+// PCM 生成 d 时长、样本值为 SampleValue 的单声道 16bit PCM
+func PCM(sampleRate int, d time.Duration) []byte {
+	n := int(int64(sampleRate) * int64(d) / int64(time.Second))
+	buf := make([]byte, n*2)
+	for i := 0; i < n; i++ {
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(SampleValue))
+	}
+	return buf
+}
+
+// DefaultHandler 模拟正常的服务端：回复 SessionStarted，每段文本调用 Speak，
+// FinishSession 后回复 SessionFinished，CancelSession 后回复 SessionCanceled
+func DefaultHandler(s *Session) {
+	if err := s.SendStarted(); err != nil {
+		return
+	}
+	for {
+		select {
+		case text, ok := <-s.Texts():
+			if !ok {
+				_ = s.SendFinished()
+				return
+			}
+			if err := s.Speak(text); err != nil {
+				return
+			}
+		case <-s.Done():
+			if s.Canceled() {
+				_ = s.SendCanceled()
+			}
+			return
+		}
+	}
+}
@@ -32,6 +32,39 @@ type WSConfig struct {
 	HandshakeTimeout time.Duration
 }
 
+// HandshakeError 服务端拒绝 WebSocket 升级（如鉴权失败返回 401/403）
+type HandshakeError struct {
+	StatusCode int
+	Header     http.Header
+	Body       string // 响应体前 1KB，通常包含服务端的错误说明
+	Err        error
+}
+
+func newHandshakeError(resp *http.Response, err error) *HandshakeError {
+	e := &HandshakeError{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Err:        err,
+	}
+	if resp.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		_ = resp.Body.Close()
+		e.Body = string(body)
+	}
+	return e
+}
+
+func (e *HandshakeError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("dial websocket: %v, status: %d, body: %s", e.Err, e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("dial websocket: %v, status: %d", e.Err, e.StatusCode)
+}
+
+func (e *HandshakeError) Unwrap() error {
+	return e.Err
+}
+
 // WsClient WebSocket 客户端接口
 type WsClient interface {
 	Recv(ctx context.Context) ([]byte, error)
@@ -77,7 +110,10 @@ func NewWsClient(ctx context.Context, config WSConfig) (WsClient, error) {
 		if conn != nil {
 			_ = conn.Close()
 		}
-		return nil, fmt.Errorf("dial websocket: %w, resp: %v", err, resp)
+		if resp != nil {
+			return nil, newHandshakeError(resp, err)
+		}
+		return nil, fmt.Errorf("dial websocket: %w", err)
 	}
 
 	// 创建客户端
@@ -134,6 +170,12 @@ func (c *wsClient) Recv(ctx context.Context) ([]byte, error) {
 		return msg, nil
 
 	case <-c.done:
+		// 连接关闭前已经收到的消息仍然返回，避免丢掉服务端关闭前发送的最后几条消息
+		select {
+		case msg := <-c.recvCh:
+			return msg, nil
+		default:
+		}
 		if err := c.getErr(); err != nil {
 			return nil, err
 		}